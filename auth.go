@@ -0,0 +1,96 @@
+package question
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Role string
+
+const (
+	// RoleUser is granted to candidates, who may only submit solutions.
+	RoleUser     Role = "user"
+	RoleEditor   Role = "editor"
+	RoleReviewer Role = "reviewer"
+	RoleAdmin    Role = "admin"
+)
+
+type (
+	User struct {
+		Name string
+		Role Role
+	}
+
+	userKey struct{}
+)
+
+// level orders roles by privilege, every role is granted what the roles below it are.
+func (r Role) level() int {
+	switch r {
+	case RoleUser:
+		return 1
+	case RoleEditor:
+		return 2
+	case RoleReviewer:
+		return 3
+	case RoleAdmin:
+		return 4
+	default:
+		return 0
+	}
+}
+
+func (r Role) IsValid() bool {
+	return r.level() > 0
+}
+
+func (u *User) Has(role Role) bool {
+	return u != nil && u.Role.level() >= role.level()
+}
+
+func WithUser(ctx context.Context, u *User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+func UserFrom(ctx context.Context) *User {
+	u, _ := ctx.Value(userKey{}).(*User)
+	return u
+}
+
+// RequireRole returns ErrUnauthorized for anonymous callers and ErrForbidden for users without the role.
+func RequireRole(ctx context.Context, role Role) error {
+	u := UserFrom(ctx)
+	if u == nil {
+		return ErrUnauthorized
+	}
+	if !u.Has(role) {
+		return fmt.Errorf("%w: %s role required", ErrForbidden, role)
+	}
+	return nil
+}
+
+// Authenticate resolves the bearer token of a request to its user. Requests without a token
+// pass through anonymously, requests with an unknown token are rejected.
+func Authenticate(tokens map[string]User) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			if header == "" {
+				return next(c)
+			}
+
+			token := strings.TrimPrefix(header, "Bearer ")
+			u, ok := tokens[token]
+			if !ok || token == header {
+				return ErrUnauthorized
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(WithUser(req.Context(), &u)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package question_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	q "github.com/codigician/question"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = q.HTTPErrorHandler
+	e.Use(q.Authenticate(map[string]q.User{"secret": {Name: "ada", Role: q.RoleEditor}}))
+	e.GET("/whoami", func(c echo.Context) error {
+		if u := q.UserFrom(c.Request().Context()); u != nil {
+			return c.String(http.StatusOK, u.Name)
+		}
+		return c.String(http.StatusOK, "anonymous")
+	})
+
+	testCases := []struct {
+		scenario           string
+		authorization      string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{scenario: "no token passes anonymously", expectedStatusCode: http.StatusOK, expectedBody: "anonymous"},
+		{scenario: "known token resolves the user", authorization: "Bearer secret", expectedStatusCode: http.StatusOK, expectedBody: "ada"},
+		{scenario: "unknown token is rejected", authorization: "Bearer guess", expectedStatusCode: http.StatusUnauthorized},
+		{scenario: "token without bearer scheme is rejected", authorization: "secret", expectedStatusCode: http.StatusUnauthorized},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+			if tC.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tC.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tC.expectedStatusCode, rec.Code)
+			if tC.expectedBody != "" {
+				assert.Equal(t, tC.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	testCases := []struct {
+		scenario    string
+		user        *q.User
+		role        q.Role
+		expectedErr error
+	}{
+		{scenario: "anonymous", role: q.RoleEditor, expectedErr: q.ErrUnauthorized},
+		{scenario: "same role", user: &q.User{Role: q.RoleEditor}, role: q.RoleEditor},
+		{scenario: "higher role", user: &q.User{Role: q.RoleAdmin}, role: q.RoleReviewer},
+		{scenario: "lower role", user: &q.User{Role: q.RoleEditor}, role: q.RoleReviewer, expectedErr: q.ErrForbidden},
+		{scenario: "user below editor", user: &q.User{Role: q.RoleUser}, role: q.RoleEditor, expectedErr: q.ErrForbidden},
+		{scenario: "unknown role", user: &q.User{Role: "guest"}, role: q.RoleUser, expectedErr: q.ErrForbidden},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			ctx := context.Background()
+			if tC.user != nil {
+				ctx = q.WithUser(ctx, tC.user)
+			}
+
+			err := q.RequireRole(ctx, tC.role)
+
+			if tC.expectedErr == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, tC.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,399 @@
+package question
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	Format       string
+	ImportAction string
+)
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+const (
+	ImportCreated ImportAction = "created"
+	ImportUpdated ImportAction = "updated"
+	ImportFailed  ImportAction = "failed"
+)
+
+type (
+	// Document is the portable form of a question used by bundles, it leaves out everything
+	// that is specific to one environment such as the id.
+	Document struct {
+		Slug       string             `json:"slug" yaml:"slug"`
+		Title      string             `json:"title" yaml:"title"`
+		Difficulty string             `json:"difficulty" yaml:"difficulty"`
+		Tags       []string           `json:"tags,omitempty" yaml:"tags,omitempty"`
+		Content    string             `json:"content" yaml:"content"`
+		Templates  map[string]string  `json:"templates,omitempty" yaml:"templates,omitempty"`
+		TestCases  []DocumentTestCase `json:"testCases" yaml:"testCases"`
+		Editorial  DocumentEditorial  `json:"editorial" yaml:"editorial"`
+	}
+
+	DocumentTestCase struct {
+		ID          string `json:"id,omitempty" yaml:"id,omitempty"`
+		Input       string `json:"input" yaml:"input"`
+		Output      string `json:"output" yaml:"output"`
+		Hidden      bool   `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+		Explanation string `json:"explanation,omitempty" yaml:"explanation,omitempty"`
+		Weight      int    `json:"weight,omitempty" yaml:"weight,omitempty"`
+	}
+
+	DocumentEditorial struct {
+		Explanation string `json:"explanation" yaml:"explanation"`
+	}
+
+	ImportOptions struct {
+		// DryRun validates and reports what would happen without writing anything.
+		DryRun bool
+		// Upsert updates the question with the same slug instead of failing on it.
+		Upsert bool
+	}
+
+	ImportResult struct {
+		Index  int
+		Slug   string
+		ID     string
+		Action ImportAction
+		Err    error
+	}
+
+	// ImportItem is a question of a bundle to import, or the error that kept its document from
+	// being read, which is reported as failed like any other question.
+	ImportItem struct {
+		Question *Algorithm
+		Err      error
+	}
+
+	// DocumentError is a well formed document of a bundle that is not a valid question document,
+	// the documents after it can still be decoded.
+	DocumentError struct {
+		Index int
+		Err   error
+	}
+
+	// BundleDecoder reads documents one at a time from a json array or a multi-document yaml stream.
+	BundleDecoder struct {
+		r       *readErrReader
+		json    *json.Decoder
+		yaml    *yaml.Decoder
+		decoded int
+		started bool
+		done    bool
+	}
+
+	// readErrReader keeps the error of the underlying reader, which decoders report as a syntax error.
+	readErrReader struct {
+		r   io.Reader
+		err error
+	}
+
+	// BundleEncoder writes documents one at a time, as a json array or as a multi-document yaml stream.
+	BundleEncoder struct {
+		w       io.Writer
+		json    *json.Encoder
+		yaml    *yaml.Encoder
+		encoded int
+	}
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatJSON, FormatYAML:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q, must be %s or %s", s, FormatJSON, FormatYAML)
+	}
+}
+
+// FormatFromMediaType maps a Content-Type header to its bundle format.
+func FormatFromMediaType(contentType string) (Format, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("invalid content type %q", contentType)
+	}
+
+	switch mediaType {
+	case "application/json":
+		return FormatJSON, nil
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("unsupported content type %q, must be json or yaml", mediaType)
+	}
+}
+
+func (f Format) MediaType() string {
+	if f == FormatYAML {
+		return "application/yaml"
+	}
+	return "application/json"
+}
+
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("decode bundle document %d: %v", e.Index, e.Err)
+}
+
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+func (r *readErrReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.err = err
+	}
+	return n, err
+}
+
+// DecodeBundle reads a json array or a multi-document yaml stream of documents and fails on the
+// first document that cannot be decoded.
+func DecodeBundle(r io.Reader, format Format) ([]Document, error) {
+	decoder, err := NewBundleDecoder(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []Document
+	for {
+		doc, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+}
+
+func NewBundleDecoder(r io.Reader, format Format) (*BundleDecoder, error) {
+	d := &BundleDecoder{r: &readErrReader{r: r}}
+	switch format {
+	case FormatJSON:
+		d.json = json.NewDecoder(d.r)
+	case FormatYAML:
+		d.yaml = yaml.NewDecoder(d.r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	return d, nil
+}
+
+// Next returns the next document and io.EOF after the last one. Unknown fields are rejected so
+// typos in hand written bundles do not go unnoticed, such a document fails with a *DocumentError
+// and the bundle can be read on. Any other error ends the bundle.
+func (d *BundleDecoder) Next() (Document, error) {
+	if d.done {
+		return Document{}, io.EOF
+	}
+
+	var (
+		doc Document
+		err error
+	)
+	if d.yaml != nil {
+		doc, err = d.nextYAML()
+	} else {
+		doc, err = d.nextJSON()
+	}
+
+	var docErr *DocumentError
+	switch {
+	case errors.Is(err, io.EOF):
+		d.done = true
+	case errors.As(err, &docErr):
+		d.decoded++
+	case err != nil:
+		d.done = true
+		if d.r.err != nil {
+			err = d.r.err
+		}
+		return Document{}, fmt.Errorf("decode %s bundle document %d: %w", d.format(), d.decoded, err)
+	default:
+		d.decoded++
+	}
+	return doc, err
+}
+
+func (d *BundleDecoder) nextJSON() (Document, error) {
+	if !d.started {
+		d.started = true
+		tok, err := d.json.Token()
+		if errors.Is(err, io.EOF) {
+			return Document{}, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return Document{}, err
+		}
+		if tok != json.Delim('[') {
+			return Document{}, fmt.Errorf("bundle must be an array, found %v", tok)
+		}
+	}
+
+	if !d.json.More() {
+		if _, err := d.json.Token(); err != nil {
+			return Document{}, err
+		}
+		return Document{}, io.EOF
+	}
+
+	var raw json.RawMessage
+	if err := d.json.Decode(&raw); err != nil {
+		return Document{}, err
+	}
+
+	var doc Document
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return Document{}, &DocumentError{Index: d.decoded, Err: err}
+	}
+	return doc, nil
+}
+
+func (d *BundleDecoder) nextYAML() (Document, error) {
+	var node yaml.Node
+	if err := d.yaml.Decode(&node); err != nil {
+		return Document{}, err
+	}
+
+	// only a decoder rejects unknown fields, so the document is decoded again on its own
+	raw, err := yaml.Marshal(&node)
+	if err != nil {
+		return Document{}, &DocumentError{Index: d.decoded, Err: err}
+	}
+	var doc Document
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return Document{}, &DocumentError{Index: d.decoded, Err: err}
+	}
+	return doc, nil
+}
+
+func (d *BundleDecoder) format() Format {
+	if d.yaml != nil {
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+func NewBundleEncoder(w io.Writer, format Format) *BundleEncoder {
+	e := &BundleEncoder{w: w}
+	if format == FormatYAML {
+		e.yaml = yaml.NewEncoder(w)
+		e.yaml.SetIndent(2)
+	} else {
+		e.json = json.NewEncoder(w)
+		e.json.SetEscapeHTML(false)
+	}
+	return e
+}
+
+func (e *BundleEncoder) Encode(doc Document) error {
+	defer func() { e.encoded++ }()
+
+	if e.yaml != nil {
+		return e.yaml.Encode(doc)
+	}
+
+	separator := ","
+	if e.encoded == 0 {
+		separator = "["
+	}
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	return e.json.Encode(doc)
+}
+
+// Close terminates the bundle, it must be called even when no document was encoded.
+func (e *BundleEncoder) Close() error {
+	if e.yaml != nil {
+		// an empty yaml stream is an empty body, the encoder fails to close without a document
+		if e.encoded == 0 {
+			return nil
+		}
+		return e.yaml.Close()
+	}
+
+	end := "]\n"
+	if e.encoded == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+func NewDocument(q *Algorithm) Document {
+	doc := Document{
+		Slug:       q.Slug,
+		Title:      q.Title,
+		Difficulty: string(q.Difficulty),
+		Tags:       q.Tags,
+		Content:    q.Content,
+		TestCases:  make([]DocumentTestCase, 0, len(q.TestCases)),
+		Editorial:  DocumentEditorial{Explanation: q.Editorial.Explanation},
+	}
+
+	for lang, code := range q.Templates {
+		if doc.Templates == nil {
+			doc.Templates = make(map[string]string, len(q.Templates))
+		}
+		doc.Templates[string(lang)] = code
+	}
+
+	for _, tc := range q.TestCases {
+		doc.TestCases = append(doc.TestCases, DocumentTestCase{
+			ID:          tc.ID,
+			Input:       tc.Input,
+			Output:      tc.Output,
+			Hidden:      tc.Hidden,
+			Explanation: tc.Explanation,
+			Weight:      tc.Weight,
+		})
+	}
+	return doc
+}
+
+func (d Document) Algorithm() *Algorithm {
+	q := &Algorithm{
+		Slug:       d.Slug,
+		Title:      d.Title,
+		Difficulty: Difficulty(d.Difficulty),
+		Tags:       d.Tags,
+		Content:    d.Content,
+		Editorial:  Editorial{Explanation: d.Editorial.Explanation},
+	}
+
+	for lang, code := range d.Templates {
+		if q.Templates == nil {
+			q.Templates = make(map[Language]string, len(d.Templates))
+		}
+		q.Templates[Language(lang)] = code
+	}
+
+	for _, tc := range d.TestCases {
+		q.TestCases = append(q.TestCases, TestCase{
+			ID:          tc.ID,
+			Input:       tc.Input,
+			Output:      tc.Output,
+			Hidden:      tc.Hidden,
+			Explanation: tc.Explanation,
+			Weight:      tc.Weight,
+		})
+	}
+	return q
+}
@@ -0,0 +1,136 @@
+package question_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundle_EncodeDecodeRoundTrip(t *testing.T) {
+	docs := []question.Document{
+		question.NewDocument(&question.Algorithm{
+			Slug:       "two-sum",
+			Title:      "Two Sum",
+			Difficulty: question.Easy,
+			Tags:       []string{"array"},
+			Content:    "Find two numbers.\nReturn their indices.",
+			Templates:  map[question.Language]string{question.Cpp: "int main() { return a < b; }"},
+			TestCases: []question.TestCase{
+				{ID: "tc-1", Input: "1 2", Output: "3", Explanation: "1 + 2"},
+				{ID: "tc-2", Input: "2 2", Output: "4", Hidden: true, Weight: 2},
+			},
+			Editorial: question.Editorial{Explanation: "Use a map."},
+		}),
+		question.NewDocument(&question.Algorithm{Slug: "empty", Title: "Empty", Difficulty: question.Hard}),
+	}
+
+	for _, format := range []question.Format{question.FormatJSON, question.FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			encoder := question.NewBundleEncoder(&buf, format)
+			for _, doc := range docs {
+				assert.Nil(t, encoder.Encode(doc))
+			}
+			assert.Nil(t, encoder.Close())
+
+			decoded, err := question.DecodeBundle(&buf, format)
+
+			assert.Nil(t, err)
+			assert.Equal(t, docs, decoded)
+		})
+	}
+}
+
+func TestBundle_EmptyBundle(t *testing.T) {
+	for _, format := range []question.Format{question.FormatJSON, question.FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.Nil(t, question.NewBundleEncoder(&buf, format).Close())
+
+			decoded, err := question.DecodeBundle(&buf, format)
+
+			assert.Nil(t, err)
+			assert.Empty(t, decoded)
+		})
+	}
+}
+
+func TestDecodeBundle_UnknownField_ReturnErr(t *testing.T) {
+	_, err := question.DecodeBundle(bytes.NewBufferString(`[{"title": "t", "difficulty": "easy", "dificulty": "hard"}]`), question.FormatJSON)
+	assert.NotNil(t, err)
+
+	_, err = question.DecodeBundle(bytes.NewBufferString("title: t\n---\ntitle: u\ntag: [a]\n"), question.FormatYAML)
+	assert.NotNil(t, err)
+}
+
+func TestBundleDecoder_UnknownField_SkipDocument(t *testing.T) {
+	testCases := []struct {
+		format question.Format
+		body   string
+	}{
+		{format: question.FormatJSON, body: `[{"title": "t"}, {"title": "u", "tag": ["a"]}, {"title": "v"}]`},
+		{format: question.FormatYAML, body: "title: t\n---\ntitle: u\ntag: [a]\n---\ntitle: v\n"},
+	}
+	for _, tC := range testCases {
+		t.Run(string(tC.format), func(t *testing.T) {
+			decoder, err := question.NewBundleDecoder(bytes.NewBufferString(tC.body), tC.format)
+			assert.Nil(t, err)
+
+			doc, err := decoder.Next()
+			assert.Nil(t, err)
+			assert.Equal(t, "t", doc.Title)
+
+			_, err = decoder.Next()
+			var docErr *question.DocumentError
+			assert.ErrorAs(t, err, &docErr)
+			assert.Equal(t, 1, docErr.Index)
+
+			doc, err = decoder.Next()
+			assert.Nil(t, err)
+			assert.Equal(t, "v", doc.Title)
+
+			_, err = decoder.Next()
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestBundleDecoder_MalformedBundle_EndBundle(t *testing.T) {
+	decoder, err := question.NewBundleDecoder(bytes.NewBufferString(`[{"title": "t"}, {"title": `), question.FormatJSON)
+	assert.Nil(t, err)
+
+	_, err = decoder.Next()
+	assert.Nil(t, err)
+	_, err = decoder.Next()
+	var docErr *question.DocumentError
+	assert.NotNil(t, err)
+	assert.False(t, errors.As(err, &docErr))
+	_, err = decoder.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestFormatFromMediaType(t *testing.T) {
+	testCases := []struct {
+		contentType string
+		expected    question.Format
+		expectedErr bool
+	}{
+		{contentType: "application/json; charset=utf-8", expected: question.FormatJSON},
+		{contentType: "application/yaml", expected: question.FormatYAML},
+		{contentType: "text/x-yaml", expected: question.FormatYAML},
+		{contentType: "text/plain", expectedErr: true},
+		{contentType: "", expectedErr: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.contentType, func(t *testing.T) {
+			format, err := question.FormatFromMediaType(tC.contentType)
+
+			assert.Equal(t, tC.expectedErr, err != nil)
+			assert.Equal(t, tC.expected, format)
+		})
+	}
+}
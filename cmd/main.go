@@ -1,56 +1,183 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/codigician/question"
+	"github.com/codigician/question/config"
+	"github.com/codigician/question/memory"
 	"github.com/codigician/question/mongo"
+	"github.com/codigician/question/sandbox"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/net/context"
 )
 
-const (
-	_shutdownTimeoutDuration = 5 * time.Second
-
-	_mongoURI  = "mongodb://localhost:27017"
-	_serverURI = ":8000"
-)
+const _maxOutputBytes = 1 << 20
 
 func main() {
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
 	e := echo.New()
+	e.HTTPErrorHandler = question.HTTPErrorHandler
+	e.Server.ReadTimeout = cfg.Server.ReadTimeout
+	e.Server.WriteTimeout = cfg.Server.WriteTimeout
+	e.Use(question.Authenticate(cfg.Auth.Users()))
 
-	questionMongodb := mongo.NewMongo(_mongoURI)
-	questionService := question.NewService(questionMongodb)
+	var (
+		repository question.Repository
+		revisions  question.RevisionRepository
+		pinger     question.Pinger
+		closers    []func(context.Context) error
+	)
+	switch cfg.Storage {
+	case config.StorageMemory:
+		log.Println("questions are kept in memory and lost on shutdown")
+		memoryRepository := memory.NewRepository()
+		repository, revisions, pinger = memoryRepository, memoryRepository, memoryRepository
+	default:
+		questionMongodb, err := openMongo(cfg.Mongo)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		repository, revisions, pinger = questionMongodb, questionMongodb, questionMongodb
+		closers = append(closers, questionMongodb.Disconnect)
+	}
+
+	// judge stays nil unless submissions are judged, so that they are refused instead of running
+	// untrusted code as the user of the server
+	var (
+		judge  question.Judge
+		runner *sandbox.Runner
+	)
+	switch {
+	case cfg.Judge.UID != 0:
+		runner = newRunner(cfg.Judge, sandbox.WithUser(cfg.Judge.UID, cfg.Judge.GID))
+	case cfg.Judge.Unsafe:
+		log.Println("submissions run as the user of the server, set judge.uid to isolate them")
+		runner = newRunner(cfg.Judge)
+	default:
+		log.Println("submissions are not judged, set judge.uid to judge them")
+	}
+	if runner != nil {
+		judge = runner
+	}
+	questionService := question.NewService(repository, judge, revisions)
 	questionHandler := question.NewHandler(questionService)
 
+	healthHandler := question.NewHealthHandler(pinger)
+
 	questionHandler.RegisterRoutes(e)
+	healthHandler.RegisterRoutes(e)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go purgeTrash(ctx, questionService.Purge, cfg.Trash.Retention, cfg.Trash.PurgeInterval)
+	if runner != nil {
+		go func() {
+			if err := runner.Warm(ctx); err != nil {
+				log.Printf("warm judge build caches: %v\n", err)
+			}
+		}()
+	}
+
+	if err := serve(ctx, e, cfg.Server.Addr, cfg.Server.ShutdownTimeout, closers...); err != nil {
+		log.Fatalln("shutdown", err)
+	}
+}
+
+func newRunner(cfg config.Judge, opts ...sandbox.Option) *sandbox.Runner {
+	return sandbox.NewRunner(sandbox.Limits{
+		Time:       cfg.TimeLimit,
+		Memory:     int64(cfg.MemoryLimitMB) << 20,
+		Output:     _maxOutputBytes,
+		Submission: cfg.SubmissionTimeout,
+		Concurrent: cfg.MaxSubmissions,
+		Processes:  cfg.MaxProcesses,
+	}, sandbox.DefaultToolchains(cfg.CacheDir), opts...)
+}
+
+// openMongo connects to mongo, retrying until it answers, ensures the indexes and migrates the
+// questions stored by older versions.
+func openMongo(cfg config.Mongo) (*mongo.Mongo, error) {
+	questionMongodb := mongo.NewMongo(cfg.URI,
+		mongo.WithDatabase(cfg.Database),
+		mongo.WithCollection(cfg.Collection))
 
 	if err := questionMongodb.Connect(context.Background()); err != nil {
-		log.Println("connection could not be established", err)
+		return nil, fmt.Errorf("connection could not be established: %w", err)
 	}
 
-	go func() {
-		if err := e.Start(_serverURI); err != nil {
-			log.Println(err)
-		}
-	}()
+	err := retry(context.Background(), cfg.ConnectRetries, cfg.ConnectBackoff, func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+		defer cancel()
+		return questionMongodb.Ping(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mongo is unreachable: %w", err)
+	}
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
-	<-quit
-	ctx, cancel := context.WithTimeout(context.Background(), _shutdownTimeoutDuration)
-	defer cancel()
+	indexCtx, cancelIndex := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancelIndex()
+	if err := questionMongodb.EnsureIndexes(indexCtx); err != nil {
+		return nil, fmt.Errorf("indexes could not be ensured: %w", err)
+	}
+	if err := questionMongodb.Migrate(indexCtx); err != nil {
+		return nil, fmt.Errorf("questions could not be migrated: %w", err)
+	}
+	return questionMongodb, nil
+}
+
+// purgeTrash purges the questions deleted more than the retention ago right away and then every
+// interval, until ctx is done. A failed purge is retried on the next tick.
+func purgeTrash(ctx context.Context, purge func(ctx context.Context, before time.Time) (int, error), retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := purge(ctx, time.Now().Add(-retention))
+		if err != nil && ctx.Err() == nil {
+			log.Printf("purge trash: %v\n", err)
+		}
+		if n > 0 {
+			log.Printf("purged %d questions from the trash\n", n)
+		}
 
-	if err := questionMongodb.Disconnect(ctx); err != nil {
-		log.Println(err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
-	log.Println("Mongo client disconnected")
+}
 
-	if err := e.Close(); err != nil {
-		log.Println(err)
+const _maxBackoff = 30 * time.Second
+
+// retry calls fn until it succeeds or fails retries+1 times, doubling the wait between attempts.
+func retry(ctx context.Context, retries int, backoff time.Duration, fn func(ctx context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= retries {
+			return err
+		}
+
+		log.Printf("attempt %d/%d failed, retrying in %s: %v\n", attempt+1, retries+1, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > _maxBackoff {
+			backoff = _maxBackoff
+		}
 	}
-	log.Println("Echo server closed")
 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry_SucceedsEventually_ReturnNil(t *testing.T) {
+	attempts := 0
+
+	err := retry(context.Background(), 3, time.Millisecond, func(ctx context.Context) error {
+		if attempts++; attempts < 3 {
+			return errors.New("unreachable")
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetry_RetriesExhausted_ReturnLastErr(t *testing.T) {
+	attempts := 0
+
+	err := retry(context.Background(), 2, time.Millisecond, func(ctx context.Context) error {
+		attempts++
+		return errors.New("unreachable")
+	})
+
+	assert.EqualError(t, err, "unreachable")
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetry_ContextCanceled_StopRetrying(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := retry(ctx, 5, time.Hour, func(ctx context.Context) error {
+		return errors.New("unreachable")
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPurgeTrash_PurgesUntilCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var cutoffs []time.Time
+
+	done := make(chan struct{})
+	go func() {
+		purgeTrash(ctx, func(ctx context.Context, before time.Time) (int, error) {
+			if cutoffs = append(cutoffs, before); len(cutoffs) == 3 {
+				cancel()
+			}
+			return 1, nil
+		}, time.Hour, time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purgeTrash did not return after the context was canceled")
+	}
+	assert.Len(t, cutoffs, 3)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), cutoffs[0], time.Second)
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codigician/question"
+	"github.com/codigician/question/mongo"
+)
+
+type (
+	// catalog is what the commands need from the question catalog, read and written either
+	// straight through the repository or through the http api of a running server.
+	catalog interface {
+		Import(ctx context.Context, docs []question.Document, opts question.ImportOptions) (*question.ImportRes, error)
+		Export(ctx context.Context, f question.Filter, encoder *question.BundleEncoder) error
+		List(ctx context.Context, f question.Filter) (*question.PageRes, error)
+		Get(ctx context.Context, id string) (*question.QuestionRes, error)
+		Delete(ctx context.Context, id string) error
+		Reindex(ctx context.Context, drop bool) ([]string, error)
+		Close(ctx context.Context) error
+	}
+
+	repositoryCatalog struct {
+		mongo   *mongo.Mongo
+		service *question.QuestionService
+	}
+
+	httpCatalog struct {
+		baseURL string
+		token   string
+		client  *http.Client
+	}
+
+	apiError struct {
+		status int
+		res    question.ErrorRes
+	}
+)
+
+func openRepositoryCatalog(ctx context.Context, m *mongo.Mongo, timeout time.Duration) (*repositoryCatalog, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := m.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("connect mongo: %w", err)
+	}
+	if err := m.Ping(ctx); err != nil {
+		return nil, fmt.Errorf("ping mongo: %w", err)
+	}
+	return &repositoryCatalog{mongo: m, service: question.NewService(m, nil, m)}, nil
+}
+
+func (r *repositoryCatalog) Import(ctx context.Context, docs []question.Document, opts question.ImportOptions) (*question.ImportRes, error) {
+	items := make([]question.ImportItem, 0, len(docs))
+	for _, doc := range docs {
+		items = append(items, question.ImportItem{Question: doc.Algorithm()})
+	}
+
+	results, err := r.service.Import(ctx, items, opts)
+	if err != nil {
+		return nil, err
+	}
+	return question.FromImportResults(opts.DryRun, results), nil
+}
+
+func (r *repositoryCatalog) Export(ctx context.Context, f question.Filter, encoder *question.BundleEncoder) error {
+	return r.service.Export(ctx, f, func(q *question.Algorithm) error {
+		return encoder.Encode(question.NewDocument(q))
+	})
+}
+
+func (r *repositoryCatalog) List(ctx context.Context, f question.Filter) (*question.PageRes, error) {
+	page, err := r.service.Filter(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return &question.PageRes{Items: question.Questions(page.Items).To(), NextCursor: page.NextCursor}, nil
+}
+
+func (r *repositoryCatalog) Get(ctx context.Context, id string) (*question.QuestionRes, error) {
+	q, err := r.service.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return question.FromQuestion(q), nil
+}
+
+func (r *repositoryCatalog) Delete(ctx context.Context, id string) error {
+	return r.service.Delete(ctx, id, question.AnyVersion)
+}
+
+func (r *repositoryCatalog) Reindex(ctx context.Context, drop bool) ([]string, error) {
+	if err := r.mongo.EnsureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	if !drop {
+		return nil, nil
+	}
+	return r.mongo.DropStaleIndexes(ctx)
+}
+
+func (r *repositoryCatalog) Close(ctx context.Context) error {
+	return r.mongo.Disconnect(ctx)
+}
+
+func newHTTPCatalog(baseURL, token string) *httpCatalog {
+	return &httpCatalog{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: time.Minute},
+	}
+}
+
+func (h *httpCatalog) Import(ctx context.Context, docs []question.Document, opts question.ImportOptions) (*question.ImportRes, error) {
+	var body bytes.Buffer
+	encoder := question.NewBundleEncoder(&body, question.FormatJSON)
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"dryRun": {strconv.FormatBool(opts.DryRun)},
+		"upsert": {strconv.FormatBool(opts.Upsert)},
+	}
+	var res question.ImportRes
+	err := h.decode(ctx, http.MethodPost, "/questions:import", query, &body, &res)
+	return &res, err
+}
+
+func (h *httpCatalog) Export(ctx context.Context, f question.Filter, encoder *question.BundleEncoder) error {
+	query := filterQuery(f)
+	query.Set("format", string(question.FormatJSON))
+
+	res, err := h.do(ctx, http.MethodGet, "/questions:export", query, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	docs, err := question.DecodeBundle(res.Body, question.FormatJSON)
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *httpCatalog) List(ctx context.Context, f question.Filter) (*question.PageRes, error) {
+	var res question.PageRes
+	err := h.decode(ctx, http.MethodGet, "/questions", filterQuery(f), nil, &res)
+	return &res, err
+}
+
+func (h *httpCatalog) Get(ctx context.Context, id string) (*question.QuestionRes, error) {
+	var res question.QuestionRes
+	err := h.decode(ctx, http.MethodGet, "/questions/"+url.PathEscape(id), nil, nil, &res)
+	return &res, err
+}
+
+func (h *httpCatalog) Delete(ctx context.Context, id string) error {
+	res, err := h.do(ctx, http.MethodDelete, "/questions/"+url.PathEscape(id), nil, nil)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (h *httpCatalog) Reindex(ctx context.Context, drop bool) ([]string, error) {
+	return nil, errors.New("reindex needs direct access to the repository, use -mongo.uri instead of -api")
+}
+
+func (h *httpCatalog) Close(ctx context.Context) error {
+	return nil
+}
+
+func (h *httpCatalog) decode(ctx context.Context, method, path string, query url.Values, body io.Reader, out interface{}) error {
+	res, err := h.do(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+// do sends the request and turns every non 2xx response into an *apiError.
+func (h *httpCatalog) do(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Response, error) {
+	target := h.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= http.StatusMultipleChoices {
+		defer res.Body.Close()
+		apiErr := &apiError{status: res.StatusCode}
+		if err := json.NewDecoder(res.Body).Decode(&apiErr.res); err != nil {
+			apiErr.res.Message = http.StatusText(res.StatusCode)
+		}
+		return nil, apiErr
+	}
+	return res, nil
+}
+
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("%d %s", e.status, e.res.Message)
+	for _, f := range e.res.Fields {
+		msg += fmt.Sprintf("\n  %s: %s", f.Field, f.Message)
+	}
+	return msg
+}
+
+func filterQuery(f question.Filter) url.Values {
+	query := url.Values{}
+	set := func(name, value string) {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	difficulties := make([]string, 0, len(f.Difficulties))
+	for _, d := range f.Difficulties {
+		difficulties = append(difficulties, string(d))
+	}
+	statuses := make([]string, 0, len(f.Statuses))
+	for _, s := range f.Statuses {
+		statuses = append(statuses, string(s))
+	}
+
+	set("q", f.Query)
+	set("tags", strings.Join(f.Tags, ","))
+	set("tagMatch", string(f.TagMatch))
+	set("excludeTags", strings.Join(f.ExcludeTags, ","))
+	set("difficulty", strings.Join(difficulties, ","))
+	set("status", strings.Join(statuses, ","))
+	set("cursor", f.Cursor)
+	set("sort", string(f.Sort))
+	if f.Limit > 0 {
+		query.Set("limit", strconv.Itoa(f.Limit))
+	}
+	return query
+}
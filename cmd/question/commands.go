@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/codigician/question"
+)
+
+type filterFlags struct {
+	query        string
+	tags         string
+	tagMatch     string
+	excludeTags  string
+	difficulties string
+	statuses     string
+}
+
+func (a *app) importQuestions(ctx context.Context, args []string) error {
+	fs := a.flagSet("import")
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without writing anything")
+	upsert := fs.Bool("upsert", false, "update questions with an existing slug instead of failing")
+	format := fs.String("format", "", "bundle format, guessed from the file extension when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("import: no bundle file given")
+	}
+
+	var docs []question.Document
+	for _, path := range fs.Args() {
+		fileDocs, err := a.readBundle(path, *format)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, fileDocs...)
+	}
+
+	return a.withCatalog(ctx, func(c catalog) error {
+		res, err := c.Import(ctx, docs, question.ImportOptions{DryRun: *dryRun, Upsert: *upsert})
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+		for _, item := range res.Items {
+			outcome := item.ID
+			if item.Error != "" {
+				outcome = item.Error
+				for _, f := range item.Fields {
+					outcome += fmt.Sprintf("; %s: %s", f.Field, f.Message)
+				}
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", item.Index, item.Slug, item.Action, outcome)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		summary := fmt.Sprintf("created %d, updated %d, failed %d", res.Created, res.Updated, res.Failed)
+		if res.DryRun {
+			summary += " (dry run)"
+		}
+		fmt.Fprintln(a.stdout, summary)
+
+		if res.Failed > 0 {
+			return fmt.Errorf("import: %d of %d questions failed", res.Failed, len(res.Items))
+		}
+		return nil
+	})
+}
+
+func (a *app) exportQuestions(ctx context.Context, args []string) error {
+	fs := a.flagSet("export")
+	format := fs.String("format", string(question.FormatYAML), "bundle format, json or yaml")
+	output := fs.String("o", "-", "file to write the bundle to, - writes stdout")
+	filters := addFilterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f, err := question.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	w := a.stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	return a.withCatalog(ctx, func(c catalog) error {
+		encoder := question.NewBundleEncoder(w, f)
+		if err := c.Export(ctx, filters.filter(), encoder); err != nil {
+			return err
+		}
+		return encoder.Close()
+	})
+}
+
+// validate checks bundles offline with the same rules the service applies, no catalog is needed.
+func (a *app) validate(ctx context.Context, args []string) error {
+	fs := a.flagSet("validate")
+	format := fs.String("format", "", "bundle format, guessed from the file extension when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("validate: no bundle file given")
+	}
+
+	var (
+		total, invalid int
+		slugs          = make(map[string]string)
+	)
+	for _, path := range fs.Args() {
+		docs, err := a.readBundle(path, *format)
+		if err != nil {
+			return err
+		}
+
+		for idx, doc := range docs {
+			total++
+			at := fmt.Sprintf("%s#%d", path, idx)
+			q := doc.Algorithm()
+			q.Normalize()
+
+			var problems []string
+			var verr *question.ValidationError
+			if err := q.Validate(); errors.As(err, &verr) {
+				for _, f := range verr.Fields {
+					problems = append(problems, fmt.Sprintf("%s: %s", f.Field, f.Message))
+				}
+			}
+			if prev, ok := slugs[q.Slug]; ok && q.Slug != "" {
+				problems = append(problems, fmt.Sprintf("slug: %q is also used by %s", q.Slug, prev))
+			}
+			slugs[q.Slug] = at
+
+			if len(problems) > 0 {
+				invalid++
+				fmt.Fprintf(a.stdout, "%s %s\n  %s\n", at, q.Slug, strings.Join(problems, "\n  "))
+			}
+		}
+	}
+
+	fmt.Fprintf(a.stdout, "%d of %d questions valid\n", total-invalid, total)
+	if invalid > 0 {
+		return fmt.Errorf("validate: %d invalid questions", invalid)
+	}
+	return nil
+}
+
+func (a *app) list(ctx context.Context, args []string) error {
+	fs := a.flagSet("list")
+	all := fs.Bool("all", false, "follow the cursors through every page")
+	limit := fs.Int("limit", 0, "page size")
+	cursor := fs.String("cursor", "", "cursor of the page to list")
+	sort := fs.String("sort", "", "sort key: created, title, difficulty or relevance")
+	filters := addFilterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f := filters.filter()
+	f.Limit, f.Cursor, f.Sort = *limit, *cursor, question.SortKey(*sort)
+
+	return a.withCatalog(ctx, func(c catalog) error {
+		w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSLUG\tDIFFICULTY\tTAGS\tTITLE")
+		for {
+			page, err := c.List(ctx, f)
+			if err != nil {
+				return err
+			}
+			for _, q := range page.Items {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", q.ID, q.Slug, q.Difficulty, strings.Join(q.Tags, ","), q.Title)
+			}
+
+			if page.NextCursor == "" {
+				break
+			}
+			if !*all {
+				fmt.Fprintf(a.stderr, "next cursor: %s\n", page.NextCursor)
+				break
+			}
+			f.Cursor = page.NextCursor
+		}
+		return w.Flush()
+	})
+}
+
+func (a *app) get(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New("get: exactly one question id expected")
+	}
+
+	return a.withCatalog(ctx, func(c catalog) error {
+		q, err := c.Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(a.stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(q)
+	})
+}
+
+func (a *app) delete(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("delete: no question id given")
+	}
+
+	return a.withCatalog(ctx, func(c catalog) error {
+		for _, id := range args {
+			if err := c.Delete(ctx, id); err != nil {
+				return fmt.Errorf("delete %s: %w", id, err)
+			}
+			fmt.Fprintf(a.stdout, "deleted %s\n", id)
+		}
+		return nil
+	})
+}
+
+func (a *app) reindex(ctx context.Context, args []string) error {
+	fs := a.flagSet("reindex")
+	drop := fs.Bool("drop", false, "drop the indexes the repository does not use")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return a.withCatalog(ctx, func(c catalog) error {
+		dropped, err := c.Reindex(ctx, *drop)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(a.stdout, "indexes ensured")
+		for _, name := range dropped {
+			fmt.Fprintf(a.stdout, "dropped %s\n", name)
+		}
+		return nil
+	})
+}
+
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("question "+name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	return fs
+}
+
+// readBundle reads a bundle file, or stdin for -, guessing the format from the extension unless given.
+func (a *app) readBundle(path, format string) ([]question.Document, error) {
+	if format == "" {
+		format = string(question.FormatJSON)
+		if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" || path == "-" {
+			format = string(question.FormatYAML)
+		}
+	}
+	f, err := question.ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = a.stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	docs, err := question.DecodeBundle(r, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return docs, nil
+}
+
+func addFilterFlags(fs *flag.FlagSet) *filterFlags {
+	var f filterFlags
+	fs.StringVar(&f.query, "q", "", "full text search query")
+	fs.StringVar(&f.tags, "tags", "", "comma-separated tags")
+	fs.StringVar(&f.tagMatch, "tag-match", "", "how tags match: any, all or none")
+	fs.StringVar(&f.excludeTags, "exclude-tags", "", "comma-separated tags to leave out")
+	fs.StringVar(&f.difficulties, "difficulty", "", "comma-separated difficulties")
+	fs.StringVar(&f.statuses, "status", "", "comma-separated statuses: draft, in_review, published or archived")
+	return &f
+}
+
+func (f *filterFlags) filter() question.Filter {
+	filter := question.Filter{
+		Query:       f.query,
+		Tags:        splitList(f.tags),
+		TagMatch:    question.TagMatch(f.tagMatch),
+		ExcludeTags: splitList(f.excludeTags),
+	}
+	for _, d := range splitList(f.difficulties) {
+		filter.Difficulties = append(filter.Difficulties, question.Difficulty(d))
+	}
+	for _, s := range splitList(f.statuses) {
+		filter.Statuses = append(filter.Statuses, question.Status(s))
+	}
+	return filter
+}
+
+func splitList(s string) (values []string) {
+	for _, value := range strings.Split(s, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,136 @@
+// Command question maintains the question catalog, either straight through the mongo repository
+// or through the http api of a running server.
+//
+//	question [-api url -token token | -mongo.uri uri] <command> [flags] [args]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/codigician/question"
+	"github.com/codigician/question/config"
+	"github.com/codigician/question/mongo"
+)
+
+type (
+	app struct {
+		stdin  io.Reader
+		stdout io.Writer
+		stderr io.Writer
+		open   func(ctx context.Context) (catalog, error)
+	}
+
+	command struct {
+		name  string
+		args  string
+		usage string
+		run   func(a *app, ctx context.Context, args []string) error
+	}
+)
+
+var _commands = []command{
+	{"import", "[-dry-run] [-upsert] [-format json|yaml] file...", "import questions from bundles, - reads stdin", (*app).importQuestions},
+	{"export", "[-format json|yaml] [-o file] [filters]", "export the matching questions as a bundle", (*app).exportQuestions},
+	{"validate", "[-format json|yaml] file...", "validate bundles without importing them", (*app).validate},
+	{"list", "[-all] [-limit n] [-cursor c] [-sort key] [filters]", "list the matching questions", (*app).list},
+	{"get", "id", "print a question as json", (*app).get},
+	{"delete", "id...", "move questions to the trash", (*app).delete},
+	{"reindex", "[-drop]", "create the mongo indexes, -drop also drops stale ones", (*app).reindex},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, os.Args[1:], os.Getenv, os.Stdin, os.Stdout, os.Stderr)
+	stop()
+
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "question:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, getenv func(string) string, stdin io.Reader, stdout, stderr io.Writer) error {
+	cfg, err := config.Load(nil, getenv)
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("question", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	apiURL := fs.String("api", getenv("QUESTION_API"), "base url of the question api, the repository is used directly when empty")
+	token := fs.String("token", getenv("QUESTION_TOKEN"), "api token sent with every request")
+	mongoURI := fs.String("mongo.uri", cfg.Mongo.URI, "mongodb connection string")
+	database := fs.String("mongo.database", cfg.Mongo.Database, "mongodb database name")
+	collection := fs.String("mongo.collection", cfg.Mongo.Collection, "mongodb question collection name")
+	fs.Usage = func() { usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	a := &app{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		open: func(ctx context.Context) (catalog, error) {
+			if *apiURL != "" {
+				return newHTTPCatalog(*apiURL, *token), nil
+			}
+			m := mongo.NewMongo(*mongoURI, mongo.WithDatabase(*database), mongo.WithCollection(*collection))
+			return openRepositoryCatalog(ctx, m, cfg.Mongo.ConnectTimeout)
+		},
+	}
+
+	// whoever reaches the repository directly is trusted like an admin of the api
+	operator := getenv("USER")
+	if operator == "" {
+		operator = "cli"
+	}
+	ctx = question.WithUser(ctx, &question.User{Name: operator, Role: question.RoleAdmin})
+
+	for _, cmd := range _commands {
+		if cmd.name == fs.Arg(0) {
+			return cmd.run(a, ctx, fs.Args()[1:])
+		}
+	}
+	fs.Usage()
+	return fmt.Errorf("unknown command %q", fs.Arg(0))
+}
+
+// withCatalog opens the catalog for a single command and closes it afterwards.
+func (a *app) withCatalog(ctx context.Context, fn func(c catalog) error) error {
+	c, err := a.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(c)
+	if closeErr := c.Close(context.Background()); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func usage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprintln(w, "usage: question [flags] <command> [command flags] [args]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, cmd := range _commands {
+		fmt.Fprintf(w, "  %-9s %s\n            %s\n", cmd.name, cmd.args, cmd.usage)
+	}
+	fmt.Fprintln(w, "\nflags:")
+	fs.PrintDefaults()
+}
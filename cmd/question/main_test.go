@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/codigician/question/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const _bundle = `slug: two-sum
+title: Two Sum
+difficulty: easy
+testCases:
+- {input: 1 2, output: "3"}
+---
+title: Broken
+difficulty: impossible
+`
+
+func TestValidate_InvalidQuestion_ReportFieldsAndFail(t *testing.T) {
+	path := writeBundle(t, "bundle.yaml", _bundle)
+
+	stdout, err := runCLI(t, "validate", path)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, stdout, path+"#1 broken")
+	assert.Contains(t, stdout, "difficulty: must be one of")
+	assert.Contains(t, stdout, "1 of 2 questions valid")
+}
+
+func TestImport_ThroughAPI_PrintResultPerQuestion(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	mockService.EXPECT().
+		Import(gomock.Any(), gomock.Len(2), question.ImportOptions{DryRun: true, Upsert: true}).
+		DoAndReturn(func(ctx context.Context, _ []question.ImportItem, _ question.ImportOptions) ([]question.ImportResult, error) {
+			assert.Equal(t, "ops", question.UserFrom(ctx).Name)
+			return []question.ImportResult{
+				{Index: 0, Slug: "two-sum", Action: question.ImportCreated},
+				{Index: 1, Slug: "broken", Action: question.ImportFailed, Err: question.ErrConflict},
+			}, nil
+		})
+	srv := newTestServer(mockService)
+	defer srv.Close()
+
+	path := writeBundle(t, "bundle.yaml", _bundle)
+	stdout, err := runCLI(t, "-api", srv.URL, "-token", "ops-token", "import", "-dry-run", "-upsert", path)
+
+	assert.EqualError(t, err, "import: 1 of 2 questions failed")
+	assert.Contains(t, stdout, "two-sum  created")
+	assert.Contains(t, stdout, "broken   failed")
+	assert.Contains(t, stdout, "created 1, updated 0, failed 1 (dry run)")
+}
+
+func TestList_ThroughAPI_PrintQuestions(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	mockService.EXPECT().
+		Filter(gomock.Any(), question.Filter{
+			Tags:         []string{"tree", "bfs"},
+			Difficulties: []question.Difficulty{question.Easy},
+			Statuses:     []question.Status{question.StatusDraft},
+			Limit:        1,
+		}).
+		Return(&question.Page{
+			Items:      []question.Algorithm{{ID: "1", Slug: "level-order", Title: "Level Order", Difficulty: question.Easy, Tags: []string{"tree", "bfs"}}},
+			NextCursor: "next",
+		}, nil)
+	srv := newTestServer(mockService)
+	defer srv.Close()
+
+	stdout, err := runCLI(t, "-api", srv.URL, "list", "-tags", "tree,bfs", "-difficulty", "easy", "-status", "draft", "-limit", "1")
+
+	assert.Nil(t, err)
+	assert.Contains(t, stdout, "level-order")
+	assert.Contains(t, stdout, "tree,bfs")
+}
+
+func TestGet_ThroughAPI_NotFound_ReturnErr(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	mockService.EXPECT().Get(gomock.Any(), "1").Return(nil, question.ErrNotFound)
+	srv := newTestServer(mockService)
+	defer srv.Close()
+
+	_, err := runCLI(t, "-api", srv.URL, "get", "1")
+
+	assert.EqualError(t, err, "404 question not found")
+}
+
+func TestReindex_ThroughAPI_ReturnErr(t *testing.T) {
+	_, err := runCLI(t, "-api", "http://localhost:0", "reindex")
+
+	assert.NotNil(t, err)
+}
+
+func TestUnknownCommand_ReturnErr(t *testing.T) {
+	_, err := runCLI(t, "publish")
+
+	assert.EqualError(t, err, `unknown command "publish"`)
+}
+
+func runCLI(t *testing.T, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	getenv := func(string) string { return "" }
+	err := run(context.Background(), args, getenv, &bytes.Buffer{}, &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func newTestServer(service *mocks.MockService) *httptest.Server {
+	e := echo.New()
+	e.HTTPErrorHandler = question.HTTPErrorHandler
+	e.Use(question.Authenticate(map[string]question.User{"ops-token": {Name: "ops", Role: question.RoleAdmin}}))
+	question.NewHandler(service).RegisterRoutes(e)
+	return httptest.NewServer(e)
+}
+
+func writeBundle(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// serve runs the server until ctx is done. It then stops accepting connections, waits up to
+// shutdownTimeout for in-flight requests to finish and only afterwards runs the closers in order,
+// so no request loses its dependencies mid-flight.
+func serve(ctx context.Context, e *echo.Echo, addr string, shutdownTimeout time.Duration, closers ...func(context.Context) error) error {
+	startErr := make(chan error, 1)
+	go func() {
+		startErr <- e.Start(addr)
+	}()
+
+	select {
+	case err := <-startErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	var shutdownErr error
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		shutdownErr = err
+		log.Println("echo server shutdown", err)
+	}
+	if err := <-startErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Println(err)
+	}
+	log.Println("Echo server closed")
+
+	for _, closer := range closers {
+		if err := closer(shutdownCtx); err != nil {
+			shutdownErr = err
+			log.Println(err)
+		}
+	}
+	return shutdownErr
+}
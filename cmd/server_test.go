@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServe_ShutdownWhileRequestInFlight_RequestCompletesBeforeClosers(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []string
+		record = func(event string) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+		}
+		started = make(chan struct{})
+	)
+
+	e, url := newTestEcho(t)
+	e.GET("/slow", func(c echo.Context) error {
+		close(started)
+		time.Sleep(300 * time.Millisecond)
+		record("request finished")
+		return c.String(http.StatusOK, "done")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(ctx, e, "", 5*time.Second, func(context.Context) error {
+			record("mongo disconnected")
+			return nil
+		})
+	}()
+
+	resCh := make(chan *http.Response, 1)
+	go func() {
+		res, err := http.Get(url + "/slow")
+		assert.Nil(t, err)
+		resCh <- res
+	}()
+
+	<-started
+	cancel()
+
+	res := <-resCh
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "done", string(body))
+
+	assert.Nil(t, <-served)
+	assert.Equal(t, []string{"request finished", "mongo disconnected"}, events)
+
+	_, err := http.Get(url + "/slow")
+	assert.NotNil(t, err, "server should not accept connections after shutdown")
+}
+
+func TestServe_ShutdownTimeoutExceeded_ReturnErrAndStillRunClosers(t *testing.T) {
+	e, url := newTestEcho(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	e.GET("/stuck", func(c echo.Context) error {
+		close(started)
+		<-release
+		return c.NoContent(http.StatusOK)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	closed := false
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(ctx, e, "", 50*time.Millisecond, func(context.Context) error {
+			closed = true
+			return nil
+		})
+	}()
+
+	go func() {
+		_, _ = http.Get(url + "/stuck")
+	}()
+
+	<-started
+	cancel()
+
+	assert.ErrorIs(t, <-served, context.DeadlineExceeded)
+	assert.True(t, closed)
+}
+
+func newTestEcho(t *testing.T) (*echo.Echo, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.Listener = listener
+	return e, "http://" + listener.Addr().String()
+}
@@ -0,0 +1,334 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codigician/question"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	StorageMongo  = "mongo"
+	StorageMemory = "memory"
+)
+
+const (
+	_envPrefix     = "QUESTION_"
+	_envConfigFile = "QUESTION_CONFIG"
+	_flagConfig    = "config"
+)
+
+type (
+	Config struct {
+		// Storage is where questions are kept, memory loses them on shutdown.
+		Storage string `yaml:"storage"`
+		Server  Server `yaml:"server"`
+		Mongo   Mongo  `yaml:"mongo"`
+		Judge   Judge  `yaml:"judge"`
+		Auth    Auth   `yaml:"auth"`
+		Trash   Trash  `yaml:"trash"`
+	}
+
+	Server struct {
+		Addr            string        `yaml:"addr"`
+		ReadTimeout     time.Duration `yaml:"readTimeout"`
+		WriteTimeout    time.Duration `yaml:"writeTimeout"`
+		ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	}
+
+	Mongo struct {
+		URI            string        `yaml:"uri"`
+		Database       string        `yaml:"database"`
+		Collection     string        `yaml:"collection"`
+		ConnectTimeout time.Duration `yaml:"connectTimeout"`
+		ConnectRetries int           `yaml:"connectRetries"`
+		ConnectBackoff time.Duration `yaml:"connectBackoff"`
+	}
+
+	Judge struct {
+		TimeLimit     time.Duration `yaml:"timeLimit"`
+		MemoryLimitMB int           `yaml:"memoryLimitMB"`
+		// SubmissionTimeout bounds judging a whole submission, it must end before the server
+		// gives up writing the verdict.
+		SubmissionTimeout time.Duration `yaml:"submissionTimeout"`
+		CacheDir          string        `yaml:"cacheDir"`
+		MaxSubmissions    int           `yaml:"maxSubmissions"`
+		MaxProcesses      int           `yaml:"maxProcesses"`
+		// UID and GID are who submissions compile as, they run as the UIDs after it. Without a
+		// UID submissions are only judged when Unsafe allows running them as the user of the server.
+		UID    int  `yaml:"uid"`
+		GID    int  `yaml:"gid"`
+		Unsafe bool `yaml:"unsafe"`
+	}
+
+	Auth struct {
+		Tokens Tokens `yaml:"tokens"`
+	}
+
+	// Trash keeps deleted questions for the retention period, they are purged for good every
+	// purge interval once it is over.
+	Trash struct {
+		Retention     time.Duration `yaml:"retention"`
+		PurgeInterval time.Duration `yaml:"purgeInterval"`
+	}
+
+	Token struct {
+		Name  string `yaml:"name"`
+		Role  string `yaml:"role"`
+		Token string `yaml:"token"`
+	}
+
+	// Tokens is set from the environment and flags as comma-separated name:role:token triples.
+	Tokens []Token
+
+	// setting binds a config field to the -name flag and the QUESTION_NAME env variable,
+	// e.g. mongo.uri is set by -mongo.uri and QUESTION_MONGO_URI.
+	setting struct {
+		name  string
+		usage string
+		field func(c *Config) interface{}
+	}
+)
+
+var _settings = []setting{
+	{"storage", "where questions are stored: mongo or memory", func(c *Config) interface{} { return &c.Storage }},
+	{"server.addr", "address the http server listens on", func(c *Config) interface{} { return &c.Server.Addr }},
+	{"server.read-timeout", "maximum duration for reading a request", func(c *Config) interface{} { return &c.Server.ReadTimeout }},
+	{"server.write-timeout", "maximum duration for writing a response", func(c *Config) interface{} { return &c.Server.WriteTimeout }},
+	{"server.shutdown-timeout", "maximum duration to drain requests on shutdown", func(c *Config) interface{} { return &c.Server.ShutdownTimeout }},
+	{"mongo.uri", "mongodb connection string", func(c *Config) interface{} { return &c.Mongo.URI }},
+	{"mongo.database", "mongodb database name", func(c *Config) interface{} { return &c.Mongo.Database }},
+	{"mongo.collection", "mongodb question collection name", func(c *Config) interface{} { return &c.Mongo.Collection }},
+	{"mongo.connect-timeout", "maximum duration of a single mongodb connection attempt", func(c *Config) interface{} { return &c.Mongo.ConnectTimeout }},
+	{"mongo.connect-retries", "number of retries before giving up connecting to mongodb", func(c *Config) interface{} { return &c.Mongo.ConnectRetries }},
+	{"mongo.connect-backoff", "initial wait between mongodb connection attempts, doubled after each one", func(c *Config) interface{} { return &c.Mongo.ConnectBackoff }},
+	{"judge.time-limit", "maximum run time of a submission per test case", func(c *Config) interface{} { return &c.Judge.TimeLimit }},
+	{"judge.memory-limit-mb", "maximum memory of a submission in megabytes", func(c *Config) interface{} { return &c.Judge.MemoryLimitMB }},
+	{"judge.submission-timeout", "maximum duration of judging a submission against every test case", func(c *Config) interface{} { return &c.Judge.SubmissionTimeout }},
+	{"judge.cache-dir", "directory of the build caches shared by submissions", func(c *Config) interface{} { return &c.Judge.CacheDir }},
+	{"judge.max-submissions", "maximum number of submissions judged at once", func(c *Config) interface{} { return &c.Judge.MaxSubmissions }},
+	{"judge.max-processes", "maximum number of processes of each user running submissions", func(c *Config) interface{} { return &c.Judge.MaxProcesses }},
+	{"judge.uid", "user id submissions compile as, they run as the ids after it, the server has to run as root", func(c *Config) interface{} { return &c.Judge.UID }},
+	{"judge.gid", "group id submissions run as", func(c *Config) interface{} { return &c.Judge.GID }},
+	{"judge.unsafe", "judge submissions as the user of the server when judge.uid is not set", func(c *Config) interface{} { return &c.Judge.Unsafe }},
+	{"auth.tokens", "api tokens as comma-separated name:role:token triples", func(c *Config) interface{} { return &c.Auth.Tokens }},
+	{"trash.retention", "how long deleted questions can be restored before they are purged", func(c *Config) interface{} { return &c.Trash.Retention }},
+	{"trash.purge-interval", "how often questions past the trash retention are purged", func(c *Config) interface{} { return &c.Trash.PurgeInterval }},
+}
+
+func Default() Config {
+	return Config{
+		Storage: StorageMongo,
+		Server: Server{
+			Addr:            ":8000",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			ShutdownTimeout: 5 * time.Second,
+		},
+		Mongo: Mongo{
+			URI:            "mongodb://localhost:27017",
+			Database:       "listing",
+			Collection:     "question",
+			ConnectTimeout: 10 * time.Second,
+			ConnectRetries: 5,
+			ConnectBackoff: time.Second,
+		},
+		Judge: Judge{
+			TimeLimit:         2 * time.Second,
+			MemoryLimitMB:     256,
+			SubmissionTimeout: 8 * time.Second,
+			CacheDir:          filepath.Join(os.TempDir(), "question-judge"),
+			MaxSubmissions:    4,
+			MaxProcesses:      256,
+		},
+		Trash: Trash{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+	}
+}
+
+// Load reads the configuration from the defaults, the optional yaml file given by -config
+// or QUESTION_CONFIG, the environment and the command line flags, later ones taking precedence.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	fs := flag.NewFlagSet("question", flag.ContinueOnError)
+	configFile := fs.String(_flagConfig, getenv(_envConfigFile), "path of the yaml config file")
+	for _, s := range _settings {
+		fs.String(s.name, "", s.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	cfg := Default()
+	if *configFile != "" {
+		if err := cfg.readFile(*configFile); err != nil {
+			return Config{}, err
+		}
+	}
+
+	for _, s := range _settings {
+		if value := getenv(s.env()); value != "" {
+			if err := s.set(&cfg, value); err != nil {
+				return Config{}, fmt.Errorf("env %s: %w", s.env(), err)
+			}
+		}
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range _settings {
+			if s.name == f.Name && err == nil {
+				if setErr := s.set(&cfg, f.Value.String()); setErr != nil {
+					err = fmt.Errorf("flag -%s: %w", s.name, setErr)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return Config{}, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func (c *Config) Validate() error {
+	var errs []string
+
+	if c.Storage != StorageMongo && c.Storage != StorageMemory {
+		errs = append(errs, fmt.Sprintf("storage must be %s or %s", StorageMongo, StorageMemory))
+	}
+	if c.Server.Addr == "" {
+		errs = append(errs, "server.addr must not be empty")
+	}
+	if !strings.HasPrefix(c.Mongo.URI, "mongodb://") && !strings.HasPrefix(c.Mongo.URI, "mongodb+srv://") {
+		errs = append(errs, "mongo.uri must start with mongodb:// or mongodb+srv://")
+	}
+	if c.Mongo.Database == "" {
+		errs = append(errs, "mongo.database must not be empty")
+	}
+	if c.Mongo.Collection == "" {
+		errs = append(errs, "mongo.collection must not be empty")
+	}
+	if c.Mongo.ConnectRetries < 0 {
+		errs = append(errs, "mongo.connect-retries must not be negative")
+	}
+	if c.Judge.MemoryLimitMB <= 0 {
+		errs = append(errs, "judge.memory-limit-mb must be positive")
+	}
+	if c.Judge.SubmissionTimeout >= c.Server.WriteTimeout {
+		errs = append(errs, "judge.submission-timeout must be shorter than server.write-timeout")
+	}
+	if c.Judge.CacheDir == "" {
+		errs = append(errs, "judge.cache-dir must not be empty")
+	}
+	if c.Judge.MaxSubmissions <= 0 {
+		errs = append(errs, "judge.max-submissions must be positive")
+	}
+	if c.Judge.MaxProcesses <= 0 {
+		errs = append(errs, "judge.max-processes must be positive")
+	}
+	if c.Judge.UID < 0 || c.Judge.GID < 0 {
+		errs = append(errs, "judge.uid and judge.gid must not be negative")
+	}
+	seen := make(map[string]bool, len(c.Auth.Tokens))
+	for i, t := range c.Auth.Tokens {
+		switch {
+		case t.Name == "" || t.Token == "":
+			errs = append(errs, fmt.Sprintf("auth.tokens[%d] must have a name and a token", i))
+		case !question.Role(t.Role).IsValid():
+			errs = append(errs, fmt.Sprintf("auth.tokens[%d] has unknown role %q", i, t.Role))
+		case seen[t.Token]:
+			errs = append(errs, fmt.Sprintf("auth.tokens[%d] reuses the token of another user", i))
+		}
+		seen[t.Token] = true
+	}
+
+	for _, s := range _settings {
+		if d, ok := s.field(c).(*time.Duration); ok && *d <= 0 {
+			errs = append(errs, s.name+" must be positive")
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New("invalid config: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (c *Config) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decode config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Users maps every token to the user it authenticates.
+func (a Auth) Users() map[string]question.User {
+	users := make(map[string]question.User, len(a.Tokens))
+	for _, t := range a.Tokens {
+		users[t.Token] = question.User{Name: t.Name, Role: question.Role(t.Role)}
+	}
+	return users
+}
+
+func (t *Tokens) Set(value string) error {
+	var tokens Tokens
+	for _, triple := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(triple), ":", 3)
+		if len(parts) != 3 {
+			return errors.New("tokens must be name:role:token triples")
+		}
+		tokens = append(tokens, Token{Name: parts[0], Role: parts[1], Token: parts[2]})
+	}
+	*t = tokens
+	return nil
+}
+
+func (s setting) env() string {
+	return _envPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(s.name))
+}
+
+func (s setting) set(c *Config, value string) error {
+	switch field := s.field(c).(type) {
+	case *string:
+		*field = value
+	case *int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*field = i
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*field = b
+	case *time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*field = d
+	case interface{ Set(string) error }:
+		return field.Set(value)
+	}
+	return nil
+}
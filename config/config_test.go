@@ -0,0 +1,142 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codigician/question"
+	"github.com/codigician/question/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_NoSource_ReturnDefault(t *testing.T) {
+	cfg, err := config.Load(nil, env(nil))
+
+	assert.Nil(t, err)
+	assert.Equal(t, config.Default(), cfg)
+}
+
+func TestLoad_FileEnvAndFlags_LaterSourcesTakePrecedence(t *testing.T) {
+	path := writeFile(t, `
+server:
+  addr: ":9000"
+  shutdownTimeout: 30s
+mongo:
+  uri: mongodb://file:27017
+  database: file-db
+  collection: file-collection
+trash:
+  retention: 168h
+`)
+
+	cfg, err := config.Load(
+		[]string{"-config", path, "-mongo.collection", "flag-collection", "-mongo.connect-retries", "0"},
+		env(map[string]string{
+			"QUESTION_MONGO_DATABASE":       "env-db",
+			"QUESTION_MONGO_COLLECTION":     "env-collection",
+			"QUESTION_TRASH_PURGE_INTERVAL": "10m",
+		}),
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, ":9000", cfg.Server.Addr)
+	assert.Equal(t, 30*time.Second, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, "mongodb://file:27017", cfg.Mongo.URI)
+	assert.Equal(t, "env-db", cfg.Mongo.Database)
+	assert.Equal(t, "flag-collection", cfg.Mongo.Collection)
+	assert.Equal(t, 0, cfg.Mongo.ConnectRetries)
+	assert.Equal(t, 7*24*time.Hour, cfg.Trash.Retention)
+	assert.Equal(t, 10*time.Minute, cfg.Trash.PurgeInterval)
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	path := writeFile(t, "mongo:\n  uri: mongodb+srv://cluster.example.com\n")
+
+	cfg, err := config.Load(nil, env(map[string]string{"QUESTION_CONFIG": path}))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "mongodb+srv://cluster.example.com", cfg.Mongo.URI)
+}
+
+func TestLoad_AuthTokens(t *testing.T) {
+	path := writeFile(t, "auth:\n  tokens:\n  - {name: ada, role: admin, token: file-secret}\n")
+
+	cfg, err := config.Load([]string{"-config", path}, env(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]question.User{"file-secret": {Name: "ada", Role: question.RoleAdmin}}, cfg.Auth.Users())
+
+	cfg, err = config.Load([]string{"-config", path}, env(map[string]string{"QUESTION_AUTH_TOKENS": "ada:editor:a:b, bob:reviewer:c"}))
+	assert.Nil(t, err)
+	assert.Equal(t, config.Tokens{
+		{Name: "ada", Role: "editor", Token: "a:b"},
+		{Name: "bob", Role: "reviewer", Token: "c"},
+	}, cfg.Auth.Tokens)
+}
+
+func TestLoad_JudgeUnsafe(t *testing.T) {
+	cfg, err := config.Load([]string{"-judge.unsafe", "true"}, env(nil))
+	assert.Nil(t, err)
+	assert.True(t, cfg.Judge.Unsafe)
+
+	cfg, err = config.Load(nil, env(map[string]string{"QUESTION_JUDGE_UNSAFE": "false"}))
+	assert.Nil(t, err)
+	assert.False(t, cfg.Judge.Unsafe)
+}
+
+func TestLoad_InvalidSources_ReturnErr(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		args     []string
+		env      map[string]string
+		file     string
+	}{
+		{scenario: "unknown flag", args: []string{"-unknown", "1"}},
+		{scenario: "malformed duration in env", env: map[string]string{"QUESTION_SERVER_READ_TIMEOUT": "ten"}},
+		{scenario: "malformed duration in flag", args: []string{"-mongo.connect-timeout", "ten"}},
+		{scenario: "malformed number in env", env: map[string]string{"QUESTION_MONGO_CONNECT_RETRIES": "many"}},
+		{scenario: "negative retries", args: []string{"-mongo.connect-retries", "-1"}},
+		{scenario: "unknown field in file", file: "server:\n  port: 8000\n"},
+		{scenario: "missing file", args: []string{"-config", "/does/not/exist.yaml"}},
+		{scenario: "invalid mongo uri", args: []string{"-mongo.uri", "localhost:27017"}},
+		{scenario: "empty database", file: "mongo:\n  database: \"\"\n"},
+		{scenario: "non positive timeout", args: []string{"-server.shutdown-timeout", "0s"}},
+		{scenario: "non positive retention", env: map[string]string{"QUESTION_TRASH_RETENTION": "0s"}},
+		{scenario: "unknown storage", args: []string{"-storage", "postgres"}},
+		{scenario: "submission timeout past write timeout", args: []string{"-judge.submission-timeout", "10s"}},
+		{scenario: "no concurrent submissions", env: map[string]string{"QUESTION_JUDGE_MAX_SUBMISSIONS": "0"}},
+		{scenario: "negative judge uid", args: []string{"-judge.uid", "-1"}},
+		{scenario: "malformed bool in env", env: map[string]string{"QUESTION_JUDGE_UNSAFE": "sure"}},
+		{scenario: "malformed tokens", env: map[string]string{"QUESTION_AUTH_TOKENS": "ada-editor"}},
+		{scenario: "unknown role", args: []string{"-auth.tokens", "ada:owner:secret"}},
+		{scenario: "reused token", file: "auth:\n  tokens:\n  - {name: ada, role: editor, token: s}\n  - {name: bob, role: admin, token: s}\n"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			args := tC.args
+			if tC.file != "" {
+				args = append(args, "-config", writeFile(t, tC.file))
+			}
+
+			_, err := config.Load(args, env(tC.env))
+
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
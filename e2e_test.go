@@ -0,0 +1,189 @@
+package question_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	q "github.com/codigician/question"
+	"github.com/codigician/question/memory"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndToEnd_CreateFilterGetAndDelete(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = q.HTTPErrorHandler
+	e.Use(q.Authenticate(map[string]q.User{"admin-token": {Name: "grace", Role: q.RoleAdmin}}))
+	repository := memory.NewRepository()
+	q.NewHandler(q.NewService(repository, nil, repository)).RegisterRoutes(e)
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	var created q.CreateQuestionRes
+	for _, req := range []q.QuestionReq{
+		{Title: "Two Sum", Difficulty: "easy", Tags: []string{"Array"}, TestCases: []q.TestCaseReq{{Input: "1 2", Output: "3"}}},
+		{Title: "Word Ladder", Difficulty: "hard", Tags: []string{"graph"}, TestCases: []q.TestCaseReq{{Input: "a b", Output: "2"}}},
+	} {
+		body, _ := json.Marshal(req)
+		create, _ := http.NewRequest(http.MethodPost, srv.URL+"/questions", bytes.NewBuffer(body))
+		create.Header.Set("Authorization", "Bearer admin-token")
+		create.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+		res, err := http.DefaultClient.Do(create)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		require.Nil(t, json.NewDecoder(res.Body).Decode(&created))
+		res.Body.Close()
+
+		// new questions are drafts, only published ones are listed
+		publish, _ := http.NewRequest(http.MethodPut, srv.URL+"/questions/"+created.ID+"/status", bytes.NewBufferString(`{"status":"published"}`))
+		publish.Header.Set("Authorization", "Bearer admin-token")
+		publish.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+		res, err = http.DefaultClient.Do(publish)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		res.Body.Close()
+	}
+
+	res, err := http.Get(srv.URL + "/questions?tag=array&difficulty=easy")
+	require.Nil(t, err)
+	var page q.PageRes
+	require.Nil(t, json.NewDecoder(res.Body).Decode(&page))
+	res.Body.Close()
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "two-sum", page.Items[0].Slug)
+
+	res, err = http.Get(fmt.Sprintf("%s/questions/%s", srv.URL, created.ID))
+	require.Nil(t, err)
+	var question q.QuestionRes
+	require.Nil(t, json.NewDecoder(res.Body).Decode(&question))
+	res.Body.Close()
+	assert.Equal(t, "Word Ladder", question.Title)
+
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/questions/%s", srv.URL, created.ID), nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	res, err = http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	res, err = http.Get(fmt.Sprintf("%s/questions/%s", srv.URL, created.ID))
+	require.Nil(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestEndToEnd_UpdateAndRestoreRevision(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = q.HTTPErrorHandler
+	e.Use(q.Authenticate(map[string]q.User{"editor-token": {Name: "ada", Role: q.RoleEditor}}))
+	repository := memory.NewRepository()
+	q.NewHandler(q.NewService(repository, nil, repository)).RegisterRoutes(e)
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	do := func(method, path, contentType, body string) *http.Response {
+		req, _ := http.NewRequest(method, srv.URL+path, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer editor-token")
+		req.Header.Set("Content-Type", contentType)
+		res, err := http.DefaultClient.Do(req)
+		require.Nil(t, err)
+		t.Cleanup(func() { res.Body.Close() })
+		return res
+	}
+
+	res := do(http.MethodPost, "/questions", echo.MIMEApplicationJSON,
+		`{"title":"Two Sum","difficulty":"easy","testCases":[{"input":"1 2","output":"3"}]}`)
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+	var created q.CreateQuestionRes
+	require.Nil(t, json.NewDecoder(res.Body).Decode(&created))
+
+	res = do(http.MethodPatch, "/questions/"+created.ID, "application/merge-patch+json", `{"title":"Two Sum II"}`)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	res = do(http.MethodGet, "/questions/"+created.ID+"/revisions", "", "")
+	var revisions q.RevisionsRes
+	require.Nil(t, json.NewDecoder(res.Body).Decode(&revisions))
+	require.Len(t, revisions.Revisions, 2)
+	assert.Equal(t, 2, revisions.Revisions[0].Number)
+	assert.Equal(t, "ada", revisions.Revisions[0].Author)
+	assert.Equal(t, []string{"title"}, revisions.Revisions[0].Fields)
+
+	res = do(http.MethodGet, "/questions/"+created.ID+"/revisions/2", "", "")
+	var revision q.RevisionRes
+	require.Nil(t, json.NewDecoder(res.Body).Decode(&revision))
+	assert.JSONEq(t, `{"title":"Two Sum II"}`, string(revision.Diff))
+	assert.Equal(t, "Two Sum II", revision.Question.Title)
+
+	res = do(http.MethodPost, "/questions/"+created.ID+"/revisions/1:restore", "", "")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var restored q.QuestionRes
+	require.Nil(t, json.NewDecoder(res.Body).Decode(&restored))
+	assert.Equal(t, "Two Sum", restored.Title)
+	assert.Equal(t, 3, restored.Version)
+	assert.Equal(t, `"3"`, res.Header.Get("ETag"))
+
+	res = do(http.MethodPost, "/questions/"+created.ID+"/revisions/1:undo", "", "")
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	res = do(http.MethodGet, "/questions/"+created.ID+"/revisions/9", "", "")
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestEndToEnd_DeleteAndRestoreFromTrash(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = q.HTTPErrorHandler
+	e.Use(q.Authenticate(map[string]q.User{"editor-token": {Name: "ada", Role: q.RoleEditor}}))
+	repository := memory.NewRepository()
+	service := q.NewService(repository, nil, repository)
+	q.NewHandler(service).RegisterRoutes(e)
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	do := func(method, path, body string) *http.Response {
+		req, _ := http.NewRequest(method, srv.URL+path, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer editor-token")
+		req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+		res, err := http.DefaultClient.Do(req)
+		require.Nil(t, err)
+		t.Cleanup(func() { res.Body.Close() })
+		return res
+	}
+
+	res := do(http.MethodPost, "/questions",
+		`{"title":"Two Sum","difficulty":"easy","testCases":[{"input":"1 2","output":"3"}]}`)
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+	var created q.CreateQuestionRes
+	require.Nil(t, json.NewDecoder(res.Body).Decode(&created))
+
+	res = do(http.MethodDelete, "/questions/"+created.ID, "")
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+	res = do(http.MethodGet, "/questions/"+created.ID, "")
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	res = do(http.MethodGet, "/questions/trash", "")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var trash q.PageRes
+	require.Nil(t, json.NewDecoder(res.Body).Decode(&trash))
+	require.Len(t, trash.Items, 1)
+	assert.Equal(t, created.ID, trash.Items[0].ID)
+	assert.NotNil(t, trash.Items[0].DeletedAt)
+
+	res = do(http.MethodPost, "/questions/"+created.ID+":restore", "")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	res = do(http.MethodGet, "/questions/"+created.ID, "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res = do(http.MethodDelete, "/questions/"+created.ID, "")
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+	n, err := service.Purge(context.Background(), time.Now().Add(time.Minute))
+	require.Nil(t, err)
+	assert.Equal(t, 1, n)
+	res = do(http.MethodPost, "/questions/"+created.ID+":restore", "")
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
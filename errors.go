@@ -0,0 +1,59 @@
+package question
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrNotFound            = errors.New("question not found")
+	ErrTemplateNotFound    = errors.New("template not found")
+	ErrTestCaseNotFound    = errors.New("test case not found")
+	ErrLastTestCase        = errors.New("last test case of a question")
+	ErrInvalidID           = errors.New("invalid question id")
+	ErrConflict            = errors.New("question conflicts with an existing one")
+	ErrInvalidCursor       = errors.New("invalid pagination cursor")
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	ErrUnauthorized        = errors.New("authentication required")
+	ErrForbidden           = errors.New("permission denied")
+	ErrPreconditionFailed  = errors.New("question was changed since it was read")
+	ErrInvalidPatch        = errors.New("invalid patch")
+	ErrRevisionNotFound    = errors.New("revision not found")
+	ErrInvalidRevision     = errors.New("invalid revision number")
+	ErrRevisionsDisabled   = errors.New("revisions are not kept")
+	ErrInvalidTransition   = errors.New("status change not allowed")
+	ErrJudgeBusy           = errors.New("too many submissions are being judged, try again later")
+	ErrJudgeDisabled       = errors.New("submissions are not judged")
+)
+
+type (
+	FieldError struct {
+		Field   string
+		Message string
+	}
+
+	ValidationError struct {
+		Fields []FieldError
+	}
+)
+
+func (e *ValidationError) Add(field, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns nil when no field failed, so callers can return it directly.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", f.Field, f.Message))
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
@@ -0,0 +1,181 @@
+package question
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	TagMatch string
+	SortKey  string
+)
+
+const (
+	TagMatchAny  TagMatch = "any"
+	TagMatchAll  TagMatch = "all"
+	TagMatchNone TagMatch = "none"
+)
+
+const (
+	SortCreated    SortKey = "created"
+	SortTitle      SortKey = "title"
+	SortDifficulty SortKey = "difficulty"
+	SortRelevance  SortKey = "relevance"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+type (
+	Filter struct {
+		Query string
+
+		Tags         []string
+		TagMatch     TagMatch
+		ExcludeTags  []string
+		Difficulties []Difficulty
+		// Statuses keeps the questions in one of the statuses, any status when empty.
+		Statuses []Status
+
+		// Deleted lists the questions in the trash instead of the ones in use.
+		Deleted bool
+
+		Limit  int
+		Cursor string
+		Sort   SortKey
+	}
+
+	Page struct {
+		Items      []Algorithm
+		NextCursor string
+	}
+
+	// Cursor points right after the last item of a page. Value holds the sort key
+	// of that item, ID breaks ties between items sharing the same value.
+	Cursor struct {
+		Sort  SortKey `json:"s"`
+		Value string  `json:"v,omitempty"`
+		ID    string  `json:"id"`
+	}
+)
+
+func (m TagMatch) IsValid() bool {
+	return m == TagMatchAny || m == TagMatchAll || m == TagMatchNone
+}
+
+func (k SortKey) IsValid() bool {
+	return k == SortCreated || k == SortTitle || k == SortDifficulty || k == SortRelevance
+}
+
+// Normalize fills in the default tag match, sort key and limit, and caps the limit at MaxLimit.
+// Searches are sorted by relevance unless asked otherwise.
+func (f *Filter) Normalize() {
+	f.Query = strings.TrimSpace(f.Query)
+	f.Tags = NormalizeTags(f.Tags)
+	f.ExcludeTags = NormalizeTags(f.ExcludeTags)
+	if f.TagMatch == "" {
+		f.TagMatch = TagMatchAny
+	}
+	if f.Sort == "" && f.Query != "" {
+		f.Sort = SortRelevance
+	}
+	if f.Sort == "" {
+		f.Sort = SortCreated
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
+}
+
+func (f *Filter) Validate() error {
+	var verr ValidationError
+
+	if !f.TagMatch.IsValid() {
+		verr.Add("tagMatch", "must be one of %s, %s, %s; got %q", TagMatchAny, TagMatchAll, TagMatchNone, f.TagMatch)
+	}
+	for i, d := range f.Difficulties {
+		if !d.IsValid() {
+			verr.Add(fmt.Sprintf("difficulty[%d]", i), "must be one of %s, %s, %s; got %q", Easy, Medium, Hard, d)
+		}
+	}
+	for i, s := range f.Statuses {
+		if !s.IsValid() {
+			verr.Add(fmt.Sprintf("status[%d]", i), "must be one of %s, %s, %s, %s; got %q", StatusDraft, StatusInReview, StatusPublished, StatusArchived, s)
+		}
+	}
+	if !f.Sort.IsValid() {
+		verr.Add("sort", "must be one of %s, %s, %s, %s; got %q", SortCreated, SortTitle, SortDifficulty, SortRelevance, f.Sort)
+	} else if f.Sort == SortRelevance && f.Query == "" {
+		verr.Add("sort", "%s requires a search query", SortRelevance)
+	}
+	if f.Limit < 0 {
+		verr.Add("limit", "must not be negative")
+	}
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	_, err := f.DecodeCursor()
+	return err
+}
+
+// DecodeCursor returns nil when the filter asks for the first page.
+func (f *Filter) DecodeCursor() (*Cursor, error) {
+	if f.Cursor == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(f.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != f.Sort {
+		return nil, fmt.Errorf("%w: cursor was issued for sort %q", ErrInvalidCursor, c.Sort)
+	}
+	return &c, nil
+}
+
+// NewPage cuts items down to the filter limit. Repositories fetch one item more than
+// the limit, so a longer result means there is a next page starting after the last kept item.
+func NewPage(items []Algorithm, f Filter) *Page {
+	if f.Limit <= 0 || len(items) <= f.Limit {
+		return &Page{Items: items}
+	}
+
+	items = items[:f.Limit]
+	return &Page{
+		Items:      items,
+		NextCursor: NewCursor(&items[len(items)-1], f.Sort).Encode(),
+	}
+}
+
+func NewCursor(q *Algorithm, sort SortKey) Cursor {
+	c := Cursor{Sort: sort, ID: q.ID}
+	switch sort {
+	case SortTitle:
+		c.Value = q.Title
+	case SortDifficulty:
+		c.Value = strconv.Itoa(q.Difficulty.Level())
+	case SortRelevance:
+		c.Value = strconv.FormatFloat(q.Score, 'g', -1, 64)
+	}
+	return c
+}
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
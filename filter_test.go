@@ -0,0 +1,44 @@
+package question_test
+
+import (
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPage_MoreItemsThanLimit_SetNextCursorAfterLastItem(t *testing.T) {
+	f := question.Filter{Limit: 2, Sort: question.SortDifficulty}
+	items := []question.Algorithm{
+		{ID: "1", Difficulty: question.Easy},
+		{ID: "2", Difficulty: question.Medium},
+		{ID: "3", Difficulty: question.Hard},
+	}
+
+	page := question.NewPage(items, f)
+
+	assert.Equal(t, items[:2], page.Items)
+
+	f.Cursor = page.NextCursor
+	c, err := f.DecodeCursor()
+	assert.Nil(t, err)
+	assert.Equal(t, &question.Cursor{Sort: question.SortDifficulty, Value: "2", ID: "2"}, c)
+}
+
+func TestNewPage_ItemsFitInLimit_NoNextCursor(t *testing.T) {
+	page := question.NewPage([]question.Algorithm{{ID: "1"}}, question.Filter{Limit: 1})
+
+	assert.Len(t, page.Items, 1)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestDecodeCursor_CursorOfAnotherSort_ReturnErrInvalidCursor(t *testing.T) {
+	f := question.Filter{
+		Sort:   question.SortTitle,
+		Cursor: question.Cursor{Sort: question.SortCreated, ID: "1"}.Encode(),
+	}
+
+	_, err := f.DecodeCursor()
+
+	assert.ErrorIs(t, err, question.ErrInvalidCursor)
+}
@@ -3,6 +3,7 @@ module github.com/codigician/question
 go 1.17
 
 require (
+	github.com/evanphx/json-patch v5.6.0+incompatible
 	github.com/golang/mock v1.4.1
 	github.com/google/uuid v1.3.0
 	github.com/stretchr/testify v1.7.0
@@ -62,5 +63,5 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/testcontainers/testcontainers-go v0.12.0
 	go.mongodb.org/mongo-driver v1.8.2
-	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
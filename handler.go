@@ -2,36 +2,208 @@ package question
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+// _maxBundleSize bounds the body of an import in bytes, larger bundles are split up by the client.
+const _maxBundleSize = 16 << 20
+
+// _maxPatchSize bounds the body of a patch in bytes, it may carry test cases like a bundle does.
+const _maxPatchSize = _maxBundleSize
+
 type (
 	Service interface {
 		Get(ctx context.Context, id string) (*Algorithm, error)
 		Create(ctx context.Context, q *Algorithm) (*Algorithm, error)
-		Filter(ctx context.Context, f Filter) ([]Algorithm, error)
-		Delete(ctx context.Context, id string) error
+		Filter(ctx context.Context, f Filter) (*Page, error)
+		Delete(ctx context.Context, id string, version int) error
+		Trash(ctx context.Context, f Filter) (*Page, error)
+		Undelete(ctx context.Context, id string) (*Algorithm, error)
 		Update(ctx context.Context, id string, q *Algorithm) error
+		Patch(ctx context.Context, id string, p Patch) (*Algorithm, error)
+		SetStatus(ctx context.Context, id string, status Status, version int) (*Algorithm, error)
+		Submit(ctx context.Context, id string, sub Submission) (*SubmissionResult, error)
+		TestCases(ctx context.Context, id string) ([]TestCase, error)
+		ReplaceTestCases(ctx context.Context, id string, testCases []TestCase) error
+		TestCase(ctx context.Context, id, testCaseID string) (*TestCase, error)
+		AddTestCase(ctx context.Context, id string, tc TestCase) (*TestCase, error)
+		UpdateTestCase(ctx context.Context, id string, tc TestCase) error
+		DeleteTestCase(ctx context.Context, id, testCaseID string) error
+		Import(ctx context.Context, items []ImportItem, opts ImportOptions) ([]ImportResult, error)
+		Export(ctx context.Context, f Filter, fn func(q *Algorithm) error) error
+		Revisions(ctx context.Context, id string) ([]Revision, error)
+		Revision(ctx context.Context, id string, number int) (*Revision, error)
+		Restore(ctx context.Context, id string, number, version int) (*Algorithm, error)
 	}
 
 	Handler struct {
 		qservice Service
 	}
 
+	// limitedReader fails once more than left bytes are read, instead of cutting the body short.
+	limitedReader struct {
+		r    io.Reader
+		left int64
+	}
+
 	CreateQuestionRes struct {
 		ID string `json:"id"`
 	}
 
-	QuestionReqRes struct {
-		Title      string   `json:"title"`
-		Content    string   `json:"content"`
-		Template   string   `json:"template"`
-		Difficulty string   `json:"difficulty"`
-		Tags       []string `json:"tags"`
+	QuestionReq struct {
+		Slug       string            `json:"slug"`
+		Title      string            `json:"title"`
+		Content    string            `json:"content"`
+		Templates  map[string]string `json:"templates"`
+		Difficulty string            `json:"difficulty"`
+		Tags       []string          `json:"tags"`
+		TestCases  []TestCaseReq     `json:"testCases"`
+		Editorial  EditorialReqRes   `json:"editorial"`
+	}
+
+	QuestionRes struct {
+		ID         string          `json:"id"`
+		Slug       string          `json:"slug"`
+		Title      string          `json:"title"`
+		Content    string          `json:"content"`
+		Languages  []string        `json:"languages"`
+		Difficulty string          `json:"difficulty"`
+		Status     string          `json:"status"`
+		Tags       []string        `json:"tags"`
+		TestCases  []TestCaseRes   `json:"testCases"`
+		Editorial  EditorialReqRes `json:"editorial"`
+		CreatedAt  time.Time       `json:"createdAt"`
+		Version    int             `json:"version"`
+		DeletedAt  *time.Time      `json:"deletedAt,omitempty"`
+	}
+
+	StatusReq struct {
+		Status string `json:"status"`
+	}
+
+	TemplateRes struct {
+		Language string `json:"language"`
+		Code     string `json:"code"`
+	}
+
+	PageRes struct {
+		Items      []*QuestionRes `json:"items"`
+		NextCursor string         `json:"nextCursor,omitempty"`
+	}
+
+	TestCaseReq struct {
+		ID          string `json:"id"`
+		Input       string `json:"input"`
+		Output      string `json:"output"`
+		Hidden      bool   `json:"hidden"`
+		Explanation string `json:"explanation"`
+		Weight      int    `json:"weight"`
+	}
+
+	// TestCaseRes is a sample test case as shown to candidates.
+	TestCaseRes struct {
+		Input       string `json:"input"`
+		Output      string `json:"output"`
+		Explanation string `json:"explanation,omitempty"`
+	}
+
+	// TestCaseDetailRes is any test case as managed by editors, hidden ones included.
+	TestCaseDetailRes struct {
+		ID          string `json:"id"`
+		Input       string `json:"input"`
+		Output      string `json:"output"`
+		Hidden      bool   `json:"hidden"`
+		Explanation string `json:"explanation"`
+		Weight      int    `json:"weight"`
+	}
+
+	TestCasesReq struct {
+		TestCases []TestCaseReq `json:"testCases"`
+	}
+
+	TestCasesRes struct {
+		TestCases []TestCaseDetailRes `json:"testCases"`
+	}
+
+	EditorialReqRes struct {
+		Explanation string `json:"explanation"`
+	}
+
+	SubmissionReq struct {
+		Language string `json:"language"`
+		Code     string `json:"code"`
+	}
+
+	SubmissionRes struct {
+		Verdict  string    `json:"verdict"`
+		Passed   int       `json:"passed"`
+		Total    int       `json:"total"`
+		Score    int       `json:"score"`
+		MaxScore int       `json:"maxScore"`
+		Cases    []CaseRes `json:"cases"`
+	}
+
+	// CaseRes carries the input and outputs of sample test cases only, hidden ones stay secret.
+	CaseRes struct {
+		Verdict        string `json:"verdict"`
+		TimeMillis     int64  `json:"timeMs"`
+		Input          string `json:"input,omitempty"`
+		ExpectedOutput string `json:"expectedOutput,omitempty"`
+		Output         string `json:"output,omitempty"`
+		Error          string `json:"error,omitempty"`
+	}
+
+	ImportRes struct {
+		DryRun  bool            `json:"dryRun"`
+		Created int             `json:"created"`
+		Updated int             `json:"updated"`
+		Failed  int             `json:"failed"`
+		Items   []ImportItemRes `json:"items"`
+	}
+
+	ImportItemRes struct {
+		Index  int             `json:"index"`
+		Slug   string          `json:"slug"`
+		ID     string          `json:"id,omitempty"`
+		Action string          `json:"action"`
+		Error  string          `json:"error,omitempty"`
+		Fields []FieldErrorRes `json:"fields,omitempty"`
+	}
+
+	RevisionsRes struct {
+		Revisions []RevisionRes `json:"revisions"`
+	}
+
+	// RevisionRes leaves out the diff and the question when revisions are listed.
+	RevisionRes struct {
+		Number    int             `json:"number"`
+		Author    string          `json:"author"`
+		CreatedAt time.Time       `json:"createdAt"`
+		Fields    []string        `json:"fields"`
+		Diff      json.RawMessage `json:"diff,omitempty"`
+		Question  *Document       `json:"question,omitempty"`
+	}
+
+	ErrorRes struct {
+		Code    string          `json:"code"`
+		Message string          `json:"message"`
+		Fields  []FieldErrorRes `json:"fields,omitempty"`
+	}
+
+	FieldErrorRes struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
 	}
 )
 
@@ -41,18 +213,51 @@ func NewHandler(qservice Service) *Handler {
 
 func (h *Handler) RegisterRoutes(router *echo.Echo) {
 	// filtering:  /questions?tag=trees&tag=bfs&tag=dfs&difficulty=easy
+	//             /questions?tags=trees,bfs&tagMatch=all&excludeTags=graphs&difficulty=easy,medium
+	// search:     /questions?q=binary+search&difficulty=easy
+	// pagination: /questions?limit=20&sort=title&cursor=<nextCursor of the previous page>
+	// workflow:   /questions?status=draft,in_review lists unpublished questions to editors
 	router.GET("/questions", h.FilterQuestions)
 
+	// bundles:    /questions:import?dryRun=true&upsert=true with a json or yaml body
+	//             /questions:export?format=yaml&tag=trees
+	router.POST("/questions\\:import", h.ImportQuestions)
+	router.GET("/questions\\:export", h.ExportQuestions)
+
+	// trash:      /questions/trash takes the same filters as /questions,
+	//             /questions/:id:restore takes a deleted question out of it
+	router.GET("/questions/trash", h.TrashQuestions)
+	router.POST("/questions/:id", h.QuestionAction)
+
 	router.GET("/questions/:id", h.GetQuestion)
+	router.GET("/questions/:id/templates/:lang", h.GetTemplate)
 
 	router.POST("/questions", h.CreateQuestion)
 
 	router.PUT("/questions/:id", h.UpdateQuestion)
+	// partial updates: application/merge-patch+json or application/json-patch+json
+	router.PATCH("/questions/:id", h.PatchQuestion)
 	router.DELETE("/questions/:id", h.DeleteQuestion)
+	router.PUT("/questions/:id/status", h.UpdateStatus)
+
+	router.POST("/questions/:id/submissions", h.SubmitSolution)
+
+	// the full test case set, hidden cases included, is only available to editors
+	router.GET("/questions/:id/testcases", h.GetTestCases)
+	router.PUT("/questions/:id/testcases", h.ReplaceTestCases)
+	router.POST("/questions/:id/testcases", h.AddTestCase)
+	router.GET("/questions/:id/testcases/:tcid", h.GetTestCase)
+	router.PUT("/questions/:id/testcases/:tcid", h.UpdateTestCase)
+	router.DELETE("/questions/:id/testcases/:tcid", h.DeleteTestCase)
+
+	// revisions: /questions/:id/revisions/3:restore updates the question back to revision 3
+	router.GET("/questions/:id/revisions", h.GetRevisions)
+	router.GET("/questions/:id/revisions/:rev", h.GetRevision)
+	router.POST("/questions/:id/revisions/:rev", h.RevisionAction)
 }
 
 func (h *Handler) CreateQuestion(c echo.Context) error {
-	var req QuestionReqRes
+	var req QuestionReq
 	if err := c.Bind(&req); err != nil {
 		return err
 	}
@@ -67,23 +272,186 @@ func (h *Handler) CreateQuestion(c echo.Context) error {
 }
 
 func (h *Handler) FilterQuestions(c echo.Context) error {
-	var filter Filter
+	filter, err := parseFilter(c)
+	if err != nil {
+		return err
+	}
 
-	if tags := c.QueryParam("tags"); tags != "" {
-		filter.Tags = strings.Split(tags, ",")
+	page, err := h.qservice.Filter(c.Request().Context(), filter)
+	if err != nil {
+		log.Printf("filter questions: %v\n", err)
+		return err
 	}
 
-	if difficulty := c.QueryParam("difficulty"); difficulty != "" {
-		filter.Difficulty = Difficulty(difficulty)
+	return c.JSON(http.StatusOK, PageRes{
+		Items:      Questions(page.Items).To(),
+		NextCursor: page.NextCursor,
+	})
+}
+
+func (h *Handler) TrashQuestions(c echo.Context) error {
+	filter, err := parseFilter(c)
+	if err != nil {
+		return err
 	}
 
-	questions, err := h.qservice.Filter(c.Request().Context(), filter)
+	page, err := h.qservice.Trash(c.Request().Context(), filter)
 	if err != nil {
-		log.Printf("filter questions: %v\n", err)
+		log.Printf("trash questions: %v\n", err)
 		return err
 	}
 
-	return c.JSON(http.StatusOK, Questions(questions).To())
+	return c.JSON(http.StatusOK, PageRes{
+		Items:      Questions(page.Items).To(),
+		NextCursor: page.NextCursor,
+	})
+}
+
+func (h *Handler) ImportQuestions(c echo.Context) error {
+	format, err := FormatFromMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, err.Error())
+	}
+
+	var opts ImportOptions
+	if opts.DryRun, err = queryBool(c, "dryRun"); err != nil {
+		return err
+	}
+	if opts.Upsert, err = queryBool(c, "upsert"); err != nil {
+		return err
+	}
+
+	if c.Request().ContentLength > _maxBundleSize {
+		return echo.ErrStatusRequestEntityTooLarge
+	}
+	decoder, err := NewBundleDecoder(&limitedReader{r: c.Request().Body, left: _maxBundleSize}, format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	var items []ImportItem
+	for {
+		doc, err := decoder.Next()
+		var docErr *DocumentError
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if errors.As(err, &docErr) {
+			items = append(items, ImportItem{Err: docErr})
+			continue
+		}
+		if errors.Is(err, echo.ErrStatusRequestEntityTooLarge) {
+			return echo.ErrStatusRequestEntityTooLarge
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		items = append(items, ImportItem{Question: doc.Algorithm()})
+	}
+
+	results, err := h.qservice.Import(c.Request().Context(), items, opts)
+	if err != nil {
+		log.Printf("import questions: %v\n", err)
+		return err
+	}
+	for _, r := range results {
+		if status, _ := errorResponse(r.Err); r.Err != nil && status == http.StatusInternalServerError {
+			log.Printf("import question %d: %v\n", r.Index, r.Err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, FromImportResults(opts.DryRun, results))
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.left < 0 {
+		return 0, echo.ErrStatusRequestEntityTooLarge
+	}
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return n, echo.ErrStatusRequestEntityTooLarge
+	}
+	return n, err
+}
+
+// ExportQuestions streams the matching questions as they are read, so errors after the first
+// question can only cut the bundle short.
+func (h *Handler) ExportQuestions(c echo.Context) error {
+	format := FormatJSON
+	if f := c.QueryParam("format"); f != "" {
+		var err error
+		if format, err = ParseFormat(f); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	filter, err := parseFilter(c)
+	if err != nil {
+		return err
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, format.MediaType())
+	encoder := NewBundleEncoder(res, format)
+	err = h.qservice.Export(c.Request().Context(), filter, func(q *Algorithm) error {
+		if err := encoder.Encode(NewDocument(q)); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	})
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		log.Printf("export questions: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+func parseFilter(c echo.Context) (Filter, error) {
+	filter := Filter{
+		Query:       c.QueryParam("q"),
+		Tags:        queryList(c, "tag", "tags"),
+		TagMatch:    TagMatch(c.QueryParam("tagMatch")),
+		ExcludeTags: queryList(c, "excludeTag", "excludeTags"),
+	}
+
+	for _, difficulty := range queryList(c, "difficulty") {
+		filter.Difficulties = append(filter.Difficulties, Difficulty(difficulty))
+	}
+	for _, status := range queryList(c, "status") {
+		filter.Statuses = append(filter.Statuses, Status(status))
+	}
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return Filter{}, echo.NewHTTPError(http.StatusBadRequest, "limit must be an integer")
+		}
+		filter.Limit = l
+	}
+
+	filter.Cursor = c.QueryParam("cursor")
+	filter.Sort = SortKey(c.QueryParam("sort"))
+	return filter, nil
+}
+
+func queryBool(c echo.Context, name string) (bool, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, echo.NewHTTPError(http.StatusBadRequest, name+" must be true or false")
+	}
+	return b, nil
 }
 
 func (h *Handler) GetQuestion(c echo.Context) error {
@@ -95,30 +463,112 @@ func (h *Handler) GetQuestion(c echo.Context) error {
 		return err
 	}
 
+	c.Response().Header().Set("ETag", etag(q.Version))
 	return c.JSON(http.StatusOK, FromQuestion(q))
 }
 
+func (h *Handler) GetTemplate(c echo.Context) error {
+	id, lang := c.Param("id"), Language(c.Param("lang"))
+
+	q, err := h.qservice.Get(c.Request().Context(), id)
+	if err != nil {
+		log.Printf("get template: %v\n", err)
+		return err
+	}
+
+	code, ok := q.Templates[lang]
+	if !ok {
+		return fmt.Errorf("%w: no %s template for question %s", ErrTemplateNotFound, lang, id)
+	}
+
+	return c.JSON(http.StatusOK, TemplateRes{Language: string(lang), Code: code})
+}
+
 func (h *Handler) UpdateQuestion(c echo.Context) error {
 	id := c.Param("id")
 
-	var req QuestionReqRes
+	var req QuestionReq
 	if err := c.Bind(&req); err != nil {
 		return err
 	}
-
-	err := h.qservice.Update(c.Request().Context(), id, req.To())
+	if req.TestCases != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "testCases are replaced with PUT /questions/:id/testcases")
+	}
+	version, err := ifMatch(c)
 	if err != nil {
+		return err
+	}
+
+	q := req.To()
+	q.Version = version
+	if err := h.qservice.Update(c.Request().Context(), id, q); err != nil {
 		log.Printf("update question: %v\n", err)
 		return err
 	}
 
+	c.Response().Header().Set("ETag", etag(q.Version))
 	return c.NoContent(http.StatusNoContent)
 }
 
+func (h *Handler) PatchQuestion(c echo.Context) error {
+	id := c.Param("id")
+
+	patchType, err := PatchTypeFromMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, err.Error())
+	}
+	if c.Request().ContentLength > _maxPatchSize {
+		return echo.ErrStatusRequestEntityTooLarge
+	}
+	body, err := io.ReadAll(&limitedReader{r: c.Request().Body, left: _maxPatchSize})
+	if err != nil {
+		return err
+	}
+	version, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+
+	q, err := h.qservice.Patch(c.Request().Context(), id, Patch{Type: patchType, Body: body, Version: version})
+	if err != nil {
+		log.Printf("patch question: %v\n", err)
+		return err
+	}
+
+	c.Response().Header().Set("ETag", etag(q.Version))
+	return c.JSON(http.StatusOK, FromQuestion(q))
+}
+
+func (h *Handler) UpdateStatus(c echo.Context) error {
+	id := c.Param("id")
+
+	var req StatusReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	version, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+
+	q, err := h.qservice.SetStatus(c.Request().Context(), id, Status(req.Status), version)
+	if err != nil {
+		log.Printf("update status: %v\n", err)
+		return err
+	}
+
+	c.Response().Header().Set("ETag", etag(q.Version))
+	return c.JSON(http.StatusOK, FromQuestion(q))
+}
+
 func (h *Handler) DeleteQuestion(c echo.Context) error {
 	id := c.Param("id")
+	version, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
 
-	if err := h.qservice.Delete(c.Request().Context(), id); err != nil {
+	if err := h.qservice.Delete(c.Request().Context(), id, version); err != nil {
 		log.Printf("delete question: %v\n", err)
 		return err
 	}
@@ -126,36 +576,470 @@ func (h *Handler) DeleteQuestion(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// QuestionAction serves custom methods on a question like RevisionAction does on a revision.
+func (h *Handler) QuestionAction(c echo.Context) error {
+	id, action := splitAction(c.Param("id"))
+	if action != "restore" {
+		return echo.ErrNotFound
+	}
+
+	q, err := h.qservice.Undelete(c.Request().Context(), id)
+	if err != nil {
+		log.Printf("restore question: %v\n", err)
+		return err
+	}
+
+	c.Response().Header().Set("ETag", etag(q.Version))
+	return c.JSON(http.StatusOK, FromQuestion(q))
+}
+
+func (h *Handler) SubmitSolution(c echo.Context) error {
+	id := c.Param("id")
+
+	var req SubmissionReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	result, err := h.qservice.Submit(c.Request().Context(), id, req.To())
+	if err != nil {
+		log.Printf("submit solution: %v\n", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, FromSubmissionResult(result))
+}
+
+func (h *Handler) GetTestCases(c echo.Context) error {
+	id := c.Param("id")
+
+	testCases, err := h.qservice.TestCases(c.Request().Context(), id)
+	if err != nil {
+		log.Printf("get test cases: %v\n", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, FromTestCases(testCases))
+}
+
+func (h *Handler) ReplaceTestCases(c echo.Context) error {
+	id := c.Param("id")
+
+	var req TestCasesReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	if err := h.qservice.ReplaceTestCases(c.Request().Context(), id, req.To()); err != nil {
+		log.Printf("replace test cases: %v\n", err)
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) GetTestCase(c echo.Context) error {
+	id, tcid := c.Param("id"), c.Param("tcid")
+
+	tc, err := h.qservice.TestCase(c.Request().Context(), id, tcid)
+	if err != nil {
+		log.Printf("get test case: %v\n", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, FromTestCase(*tc))
+}
+
+func (h *Handler) AddTestCase(c echo.Context) error {
+	id := c.Param("id")
+
+	var req TestCaseReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	tc, err := h.qservice.AddTestCase(c.Request().Context(), id, req.To())
+	if err != nil {
+		log.Printf("add test case: %v\n", err)
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, FromTestCase(*tc))
+}
+
+func (h *Handler) UpdateTestCase(c echo.Context) error {
+	id := c.Param("id")
+
+	var req TestCaseReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	tc := req.To()
+	tc.ID = c.Param("tcid")
+	if err := h.qservice.UpdateTestCase(c.Request().Context(), id, tc); err != nil {
+		log.Printf("update test case: %v\n", err)
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) DeleteTestCase(c echo.Context) error {
+	id, tcid := c.Param("id"), c.Param("tcid")
+
+	if err := h.qservice.DeleteTestCase(c.Request().Context(), id, tcid); err != nil {
+		log.Printf("delete test case: %v\n", err)
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) GetRevisions(c echo.Context) error {
+	id := c.Param("id")
+
+	revisions, err := h.qservice.Revisions(c.Request().Context(), id)
+	if err != nil {
+		log.Printf("get revisions: %v\n", err)
+		return err
+	}
+
+	res := RevisionsRes{Revisions: make([]RevisionRes, 0, len(revisions))}
+	for idx := range revisions {
+		res.Revisions = append(res.Revisions, FromRevision(&revisions[idx], false))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) GetRevision(c echo.Context) error {
+	id := c.Param("id")
+	number, err := ParseRevisionNumber(c.Param("rev"))
+	if err != nil {
+		return err
+	}
+
+	rev, err := h.qservice.Revision(c.Request().Context(), id, number)
+	if err != nil {
+		log.Printf("get revision: %v\n", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, FromRevision(rev, true))
+}
+
+// RevisionAction serves custom methods on a revision, echo cannot route a static suffix
+// after a path parameter.
+func (h *Handler) RevisionAction(c echo.Context) error {
+	rev, action := splitAction(c.Param("rev"))
+	if action != "restore" {
+		return echo.ErrNotFound
+	}
+
+	id := c.Param("id")
+	number, err := ParseRevisionNumber(rev)
+	if err != nil {
+		return err
+	}
+	version, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+
+	q, err := h.qservice.Restore(c.Request().Context(), id, number, version)
+	if err != nil {
+		log.Printf("restore revision: %v\n", err)
+		return err
+	}
+
+	c.Response().Header().Set("ETag", etag(q.Version))
+	return c.JSON(http.StatusOK, FromQuestion(q))
+}
+
+// etag is the entity tag of a question version, the If-Match header of writes sends it back.
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// ifMatch returns the version the If-Match header asks for, AnyVersion when it is missing or *.
+// A tag this api never issued cannot match any version.
+func ifMatch(c echo.Context) (int, error) {
+	header := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return AnyVersion, nil
+	}
+
+	value, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err != nil {
+		return 0, fmt.Errorf("%w: malformed If-Match %s", ErrPreconditionFailed, header)
+	}
+	// versions start at 1, no question ever had the tag "0"
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%w: unknown entity tag %s", ErrPreconditionFailed, header)
+	}
+	return version, nil
+}
+
+// splitAction splits a path parameter like 3:restore into the value and the custom method.
+func splitAction(param string) (value, action string) {
+	if idx := strings.LastIndex(param, ":"); idx >= 0 {
+		return param[:idx], param[idx+1:]
+	}
+	return param, ""
+}
+
+// queryList collects the values of repeated and comma-separated query params, e.g. tag=a&tag=b and tags=a,b.
+func queryList(c echo.Context, names ...string) (values []string) {
+	params := c.QueryParams()
+	for _, name := range names {
+		for _, param := range params[name] {
+			for _, value := range strings.Split(param, ",") {
+				if value = strings.TrimSpace(value); value != "" {
+					values = append(values, value)
+				}
+			}
+		}
+	}
+	return values
+}
+
+// HTTPErrorHandler maps domain errors to status codes and writes them as ErrorRes.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status, res := errorResponse(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("%s %s: %v\n", c.Request().Method, c.Request().URL.Path, err)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, res)
+	}
+	if err != nil {
+		log.Printf("write error response: %v\n", err)
+	}
+}
+
+func errorResponse(err error) (int, ErrorRes) {
+	var (
+		httpErr       *echo.HTTPError
+		validationErr *ValidationError
+		docErr        *DocumentError
+	)
+
+	switch {
+	case errors.As(err, &validationErr):
+		res := newErrorRes(http.StatusUnprocessableEntity, "validation failed")
+		for _, f := range validationErr.Fields {
+			res.Fields = append(res.Fields, FieldErrorRes{Field: f.Field, Message: f.Message})
+		}
+		return http.StatusUnprocessableEntity, res
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrTemplateNotFound), errors.Is(err, ErrTestCaseNotFound), errors.Is(err, ErrRevisionNotFound):
+		return http.StatusNotFound, newErrorRes(http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrInvalidID), errors.Is(err, ErrInvalidCursor), errors.Is(err, ErrUnsupportedLanguage), errors.Is(err, ErrInvalidPatch), errors.Is(err, ErrInvalidRevision),
+		errors.As(err, &docErr):
+		return http.StatusBadRequest, newErrorRes(http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrConflict), errors.Is(err, ErrInvalidTransition):
+		return http.StatusConflict, newErrorRes(http.StatusConflict, err.Error())
+	case errors.Is(err, ErrPreconditionFailed):
+		return http.StatusPreconditionFailed, newErrorRes(http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusUnauthorized, newErrorRes(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden, newErrorRes(http.StatusForbidden, err.Error())
+	case errors.Is(err, ErrJudgeBusy):
+		return http.StatusServiceUnavailable, newErrorRes(http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, ErrRevisionsDisabled), errors.Is(err, ErrJudgeDisabled):
+		return http.StatusNotImplemented, newErrorRes(http.StatusNotImplemented, err.Error())
+	case errors.As(err, &httpErr):
+		return httpErr.Code, newErrorRes(httpErr.Code, fmt.Sprint(httpErr.Message))
+	default:
+		return http.StatusInternalServerError, newErrorRes(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+}
+
+func newErrorRes(status int, message string) ErrorRes {
+	return ErrorRes{
+		Code:    strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_")),
+		Message: message,
+	}
+}
+
 type Questions []Algorithm
 
-func (questions Questions) To() (filterRes []*QuestionReqRes) {
+func (questions Questions) To() []*QuestionRes {
+	filterRes := make([]*QuestionRes, 0, len(questions))
 	for idx := range questions {
 		filterRes = append(filterRes, FromQuestion(&questions[idx]))
 	}
 	return filterRes
 }
 
-func FromQuestion(q *Algorithm) *QuestionReqRes {
+func FromQuestion(q *Algorithm) *QuestionRes {
 	var tags []string
 	for _, tag := range q.Tags {
 		tags = append(tags, string(tag))
 	}
 
-	return &QuestionReqRes{
+	languages := make([]string, 0, len(q.Templates))
+	for lang := range q.Templates {
+		languages = append(languages, string(lang))
+	}
+	sort.Strings(languages)
+
+	var testCases []TestCaseRes
+	for _, tc := range q.TestCases {
+		if tc.Hidden {
+			continue
+		}
+		testCases = append(testCases, TestCaseRes{Input: tc.Input, Output: tc.Output, Explanation: tc.Explanation})
+	}
+
+	res := &QuestionRes{
+		ID:         q.ID,
+		Slug:       q.Slug,
 		Title:      q.Title,
 		Content:    q.Content,
-		Template:   q.Template,
+		Languages:  languages,
 		Difficulty: string(q.Difficulty),
+		Status:     string(q.Status),
 		Tags:       tags,
+		TestCases:  testCases,
+		Editorial:  EditorialReqRes{Explanation: q.Editorial.Explanation},
+		CreatedAt:  q.CreatedAt,
+		Version:    q.Version,
+	}
+	if !q.DeletedAt.IsZero() {
+		deletedAt := q.DeletedAt
+		res.DeletedAt = &deletedAt
 	}
+	return res
 }
 
-func (r QuestionReqRes) To() *Algorithm {
+func (r QuestionReq) To() *Algorithm {
+	var templates map[Language]string
+	for lang, code := range r.Templates {
+		if templates == nil {
+			templates = make(map[Language]string, len(r.Templates))
+		}
+		templates[Language(lang)] = code
+	}
+
 	return &Algorithm{
+		Slug:       r.Slug,
 		Title:      r.Title,
 		Content:    r.Content,
-		Template:   r.Template,
+		Templates:  templates,
 		Difficulty: Difficulty(r.Difficulty),
 		Tags:       r.Tags,
+		TestCases:  TestCasesReq{r.TestCases}.To(),
+		Editorial:  Editorial{Explanation: r.Editorial.Explanation},
+	}
+}
+
+func (r TestCasesReq) To() []TestCase {
+	var testCases []TestCase
+	for _, tc := range r.TestCases {
+		testCases = append(testCases, tc.To())
+	}
+	return testCases
+}
+
+func (r TestCaseReq) To() TestCase {
+	return TestCase{
+		ID:          r.ID,
+		Input:       r.Input,
+		Output:      r.Output,
+		Hidden:      r.Hidden,
+		Explanation: r.Explanation,
+		Weight:      r.Weight,
+	}
+}
+
+func FromTestCases(testCases []TestCase) *TestCasesRes {
+	res := &TestCasesRes{TestCases: make([]TestCaseDetailRes, 0, len(testCases))}
+	for _, tc := range testCases {
+		res.TestCases = append(res.TestCases, FromTestCase(tc))
+	}
+	return res
+}
+
+func FromTestCase(tc TestCase) TestCaseDetailRes {
+	return TestCaseDetailRes{
+		ID:          tc.ID,
+		Input:       tc.Input,
+		Output:      tc.Output,
+		Hidden:      tc.Hidden,
+		Explanation: tc.Explanation,
+		Weight:      tc.Weight,
+	}
+}
+
+func FromImportResults(dryRun bool, results []ImportResult) *ImportRes {
+	res := &ImportRes{DryRun: dryRun, Items: make([]ImportItemRes, 0, len(results))}
+	for _, r := range results {
+		item := ImportItemRes{Index: r.Index, Slug: r.Slug, ID: r.ID, Action: string(r.Action)}
+		switch r.Action {
+		case ImportCreated:
+			res.Created++
+		case ImportUpdated:
+			res.Updated++
+		case ImportFailed:
+			res.Failed++
+		}
+
+		if r.Err != nil {
+			_, errRes := errorResponse(r.Err)
+			item.Error, item.Fields = errRes.Message, errRes.Fields
+		}
+		res.Items = append(res.Items, item)
+	}
+	return res
+}
+
+func FromRevision(rev *Revision, detailed bool) RevisionRes {
+	res := RevisionRes{Number: rev.Number, Author: rev.Author, CreatedAt: rev.CreatedAt, Fields: make([]string, 0, len(rev.Fields))}
+	for _, field := range rev.Fields {
+		res.Fields = append(res.Fields, string(field))
+	}
+	if detailed {
+		doc := NewDocument(&rev.Question)
+		res.Diff, res.Question = rev.Diff, &doc
+	}
+	return res
+}
+
+func (r SubmissionReq) To() Submission {
+	return Submission{Language: Language(r.Language), Code: r.Code}
+}
+
+func FromSubmissionResult(result *SubmissionResult) *SubmissionRes {
+	res := &SubmissionRes{
+		Verdict:  string(result.Verdict),
+		Total:    len(result.Cases),
+		Score:    result.Score,
+		MaxScore: result.MaxScore,
+		Cases:    make([]CaseRes, 0, len(result.Cases)),
+	}
+
+	for idx, c := range result.Cases {
+		if c.Verdict == Accepted {
+			res.Passed++
+		}
+
+		caseRes := CaseRes{Verdict: string(c.Verdict), TimeMillis: c.Duration.Milliseconds()}
+		if idx < len(result.TestCases) && !result.TestCases[idx].Hidden {
+			caseRes.Input = result.TestCases[idx].Input
+			caseRes.ExpectedOutput = result.TestCases[idx].Output
+			caseRes.Output = c.Output
+			caseRes.Error = c.Error
+		}
+		res.Cases = append(res.Cases, caseRes)
 	}
+	return res
 }
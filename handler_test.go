@@ -2,13 +2,17 @@ package question_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
@@ -34,25 +38,54 @@ func TestFilterQuestions(t *testing.T) {
 			scenario:           "Given valid query string it should call service with empty filter and return status ok",
 			givenQueryString:   "?tags=tag1&difficulty=easy",
 			expectedStatusCode: http.StatusOK,
-			expectedFilter:     q.Filter{Tags: []string{"tag1"}, Difficulty: q.Easy},
+			expectedFilter:     q.Filter{Tags: []string{"tag1"}, Difficulties: []q.Difficulty{q.Easy}},
+		},
+		{
+			scenario:           "Given repeated and comma separated tags it should call service with all of them",
+			givenQueryString:   "?tag=trees&tag=bfs&tags=dfs,graphs&tagMatch=all&excludeTag=dp&excludeTags=greedy,math&difficulty=easy,medium&difficulty=hard",
+			expectedStatusCode: http.StatusOK,
+			expectedFilter: q.Filter{
+				Tags:         []string{"trees", "bfs", "dfs", "graphs"},
+				TagMatch:     q.TagMatchAll,
+				ExcludeTags:  []string{"dp", "greedy", "math"},
+				Difficulties: []q.Difficulty{q.Easy, q.Medium, q.Hard},
+			},
 		},
 		{
 			scenario:           "Given no query string it should call service with empty filter and return status ok",
 			expectedStatusCode: http.StatusOK,
 		},
+		{
+			scenario:           "Given search query string it should call service with query and other filters",
+			givenQueryString:   "?q=binary+search&tags=array&difficulty=easy",
+			expectedStatusCode: http.StatusOK,
+			expectedFilter:     q.Filter{Query: "binary search", Tags: []string{"array"}, Difficulties: []q.Difficulty{q.Easy}},
+		},
+		{
+			scenario:           "Given pagination query string it should call service with limit, cursor and sort",
+			givenQueryString:   "?limit=10&cursor=abc&sort=title",
+			expectedStatusCode: http.StatusOK,
+			expectedFilter:     q.Filter{Limit: 10, Cursor: "abc", Sort: q.SortTitle},
+		},
+		{
+			scenario:           "Given statuses it should call service with them",
+			givenQueryString:   "?status=draft,in_review",
+			expectedStatusCode: http.StatusOK,
+			expectedFilter:     q.Filter{Statuses: []q.Status{q.StatusDraft, q.StatusInReview}},
+		},
 		{
 			scenario:           "Given valid query string it should call service when service fails it should return internal server error",
 			givenQueryString:   "?difficulty=hard",
 			mockErr:            errors.New("an error"),
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedFilter:     q.Filter{Difficulty: q.Hard},
+			expectedFilter:     q.Filter{Difficulties: []q.Difficulty{q.Hard}},
 		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.scenario, func(t *testing.T) {
 			mockService.EXPECT().
 				Filter(gomock.Any(), tC.expectedFilter).
-				Return(nil, tC.mockErr)
+				Return(&q.Page{}, tC.mockErr)
 
 			res, err := http.Get(fmt.Sprintf("%s/questions%s", srv.URL, tC.givenQueryString))
 			log.Println("error", err)
@@ -62,6 +95,38 @@ func TestFilterQuestions(t *testing.T) {
 	}
 }
 
+func TestFilterQuestions_ReturnPageEnvelope(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	mockService.EXPECT().
+		Filter(gomock.Any(), q.Filter{Limit: 1}).
+		Return(&q.Page{Items: []q.Algorithm{{ID: "1", Title: "title"}}, NextCursor: "next"}, nil)
+
+	res, err := http.Get(srv.URL + "/questions?limit=1")
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	var actual q.PageRes
+	assert.Nil(t, json.NewDecoder(res.Body).Decode(&actual))
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "next", actual.NextCursor)
+	assert.Len(t, actual.Items, 1)
+	assert.Equal(t, "1", actual.Items[0].ID)
+}
+
+func TestFilterQuestions_InvalidLimit_Return400(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/questions?limit=ten")
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
 func TestCreateQuestion(t *testing.T) {
 	mockService := mocks.NewMockService(gomock.NewController(t))
 	srv := createTestServerAndRegisterRoutes(mockService)
@@ -81,17 +146,31 @@ func TestCreateQuestion(t *testing.T) {
 		},
 		{
 			scenario:             "Given valid request body service call fails it should return 500",
-			givenReqBody:         q.QuestionReqRes{},
+			givenReqBody:         q.QuestionReq{},
 			expectedAlgoQuestion: &q.Algorithm{},
 			expectedStatusCode:   http.StatusInternalServerError,
 			mockErr:              errors.New("an error"),
 		},
 		{
 			scenario:             "Given valid request body service call succeeds it should return 201",
-			givenReqBody:         q.QuestionReqRes{Title: "title", Content: "content"},
+			givenReqBody:         q.QuestionReq{Title: "title", Content: "content"},
 			expectedAlgoQuestion: &q.Algorithm{Title: "title", Content: "content"},
 			expectedStatusCode:   http.StatusCreated,
 		},
+		{
+			scenario: "Given request body with test cases and editorial it should pass them to service and return 201",
+			givenReqBody: q.QuestionReq{
+				Title:     "title",
+				TestCases: []q.TestCaseReq{{Input: "in", Output: "out", Hidden: true}},
+				Editorial: q.EditorialReqRes{Explanation: "explanation"},
+			},
+			expectedAlgoQuestion: &q.Algorithm{
+				Title:     "title",
+				TestCases: []q.TestCase{{Input: "in", Output: "out", Hidden: true}},
+				Editorial: q.Editorial{Explanation: "explanation"},
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
 	}
 
 	for _, tC := range testCases {
@@ -149,6 +228,90 @@ func TestGetQuestion(t *testing.T) {
 	}
 }
 
+func TestGetQuestion_HiddenTestCasesLeftOut(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	mockService.EXPECT().
+		Get(gomock.Any(), "1").
+		Return(&q.Algorithm{
+			ID:        "1",
+			Title:     "title",
+			Templates: map[q.Language]string{q.Python: "def solve():", q.Go: "package main"},
+			TestCases: []q.TestCase{
+				{Input: "sample input", Output: "sample output", Explanation: "sums both"},
+				{Input: "hidden input", Output: "hidden output", Hidden: true, Weight: 3},
+			},
+			Editorial: q.Editorial{Explanation: "explanation"},
+		}, nil)
+
+	res, err := http.Get(fmt.Sprintf("%s/questions/1", srv.URL))
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	var actual q.QuestionRes
+	assert.Nil(t, json.NewDecoder(res.Body).Decode(&actual))
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, q.QuestionRes{
+		ID:        "1",
+		Title:     "title",
+		Languages: []string{"go", "python"},
+		TestCases: []q.TestCaseRes{{Input: "sample input", Output: "sample output", Explanation: "sums both"}},
+		Editorial: q.EditorialReqRes{Explanation: "explanation"},
+	}, actual)
+}
+
+func TestGetTemplate(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	testCases := []struct {
+		scenario           string
+		givenLanguage      string
+		mockErr            error
+		expectedStatusCode int
+		expectedTemplate   q.TemplateRes
+	}{
+		{
+			scenario:           "Given language the question has a template for it should return 200",
+			givenLanguage:      "python",
+			expectedStatusCode: http.StatusOK,
+			expectedTemplate:   q.TemplateRes{Language: "python", Code: "def solve():"},
+		},
+		{
+			scenario:           "Given language the question has no template for it should return 404",
+			givenLanguage:      "java",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			scenario:           "Given not existing question it should return 404",
+			givenLanguage:      "python",
+			mockErr:            q.ErrNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			mockService.EXPECT().
+				Get(gomock.Any(), "1").
+				Return(&q.Algorithm{Templates: map[q.Language]string{q.Python: "def solve():"}}, tC.mockErr)
+
+			res, err := http.Get(fmt.Sprintf("%s/questions/1/templates/%s", srv.URL, tC.givenLanguage))
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tC.expectedStatusCode, res.StatusCode)
+			if tC.expectedStatusCode == http.StatusOK {
+				var actual q.TemplateRes
+				assert.Nil(t, json.NewDecoder(res.Body).Decode(&actual))
+				assert.Equal(t, tC.expectedTemplate, actual)
+			}
+		})
+	}
+}
+
 func TestUpdateQuestion(t *testing.T) {
 	mockService := mocks.NewMockService(gomock.NewController(t))
 	srv := createTestServerAndRegisterRoutes(mockService)
@@ -172,18 +335,24 @@ func TestUpdateQuestion(t *testing.T) {
 			givenQuestion:      "invalid request body",
 			expectedStatusCode: http.StatusBadRequest,
 		},
+		{
+			scenario:           "Given a request body with test cases it should return 400",
+			givenQuestionID:    "4",
+			givenQuestion:      q.QuestionReq{Title: "title", TestCases: []q.TestCaseReq{{Input: "1", Output: "1"}}},
+			expectedStatusCode: http.StatusBadRequest,
+		},
 		{
 			scenario:           "Given valid question id and valid request body it should return 200",
 			givenQuestionID:    "2",
-			givenQuestion:      q.QuestionReqRes{Title: "title", Content: "content"},
-			expectedQuestion:   &q.Algorithm{Title: "title", Content: "content"},
+			givenQuestion:      q.QuestionReq{Title: "title", Content: "content"},
+			expectedQuestion:   &q.Algorithm{Title: "title", Content: "content", Version: q.AnyVersion},
 			expectedStatusCode: http.StatusNoContent,
 		},
 		{
 			scenario:           "Given valid question id, valid request body, service fails it should return 500",
 			givenQuestionID:    "3",
-			givenQuestion:      q.QuestionReqRes{Title: "title", Content: "content"},
-			expectedQuestion:   &q.Algorithm{Title: "title", Content: "content"},
+			givenQuestion:      q.QuestionReq{Title: "title", Content: "content"},
+			expectedQuestion:   &q.Algorithm{Title: "title", Content: "content", Version: q.AnyVersion},
 			expectedStatusCode: http.StatusInternalServerError,
 			mockErr:            errors.New("an error"),
 		},
@@ -238,7 +407,7 @@ func TestDeleteQuestion(t *testing.T) {
 	for _, tC := range testCases {
 		t.Run(tC.scenario, func(t *testing.T) {
 			mockService.EXPECT().
-				Delete(gomock.Any(), tC.givenQuestionID).
+				Delete(gomock.Any(), tC.givenQuestionID, q.AnyVersion).
 				Return(tC.mockErr)
 
 			req, _ := http.NewRequest("DELETE", fmt.Sprintf("%s/questions/%s", srv.URL, tC.givenQuestionID), nil)
@@ -250,8 +419,882 @@ func TestDeleteQuestion(t *testing.T) {
 	}
 }
 
+func TestConditionalWrites(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	mockService.EXPECT().Get(gomock.Any(), "1").Return(&q.Algorithm{ID: "1", Version: 3}, nil)
+	res, err := http.Get(srv.URL + "/questions/1")
+	assert.Nil(t, err)
+	assert.Equal(t, `"3"`, res.Header.Get("ETag"))
+
+	testCases := []struct {
+		scenario           string
+		method             string
+		givenIfMatch       string
+		expectedVersion    int
+		mockErr            error
+		expectedStatusCode int
+	}{
+		{
+			scenario:           "Given no If-Match it should update any version",
+			method:             http.MethodPut,
+			expectedVersion:    q.AnyVersion,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			scenario:           "Given the current etag it should update and return the next etag",
+			method:             http.MethodPut,
+			givenIfMatch:       `"3"`,
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			scenario:           "Given a stale etag it should return 412",
+			method:             http.MethodPut,
+			givenIfMatch:       `W/"2"`,
+			expectedVersion:    2,
+			mockErr:            q.ErrPreconditionFailed,
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			scenario:           "Given a stale etag on delete it should return 412",
+			method:             http.MethodDelete,
+			givenIfMatch:       `"2"`,
+			expectedVersion:    2,
+			mockErr:            q.ErrPreconditionFailed,
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			scenario:           "Given * it should delete any version",
+			method:             http.MethodDelete,
+			givenIfMatch:       "*",
+			expectedVersion:    q.AnyVersion,
+			expectedStatusCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			var body io.Reader
+			if tC.method == http.MethodPut {
+				mockService.EXPECT().
+					Update(gomock.Any(), "1", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, question *q.Algorithm) error {
+						assert.Equal(t, tC.expectedVersion, question.Version)
+						question.Version = 4
+						return tC.mockErr
+					})
+				body = strings.NewReader(`{"title":"title"}`)
+			} else {
+				mockService.EXPECT().Delete(gomock.Any(), "1", tC.expectedVersion).Return(tC.mockErr)
+			}
+
+			req, _ := http.NewRequest(tC.method, srv.URL+"/questions/1", body)
+			req.Header.Set("Content-Type", "application/json")
+			if tC.givenIfMatch != "" {
+				req.Header.Set("If-Match", tC.givenIfMatch)
+			}
+			res, err := http.DefaultClient.Do(req)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tC.expectedStatusCode, res.StatusCode)
+			if tC.method == http.MethodPut && tC.mockErr == nil {
+				assert.Equal(t, `"4"`, res.Header.Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestConditionalWrites_VersionZero_Return412WithoutCallingService(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	mockService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockService.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			req, _ := http.NewRequest(method, srv.URL+"/questions/1", strings.NewReader(`{"title":"title"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", `"0"`)
+			res, err := http.DefaultClient.Do(req)
+
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+		})
+	}
+}
+
+func TestPatchQuestion(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	testCases := []struct {
+		scenario           string
+		givenContentType   string
+		givenIfMatch       string
+		expectedPatch      q.Patch
+		mockErr            error
+		expectedStatusCode int
+	}{
+		{
+			scenario:           "Given a merge patch it should return the patched question",
+			givenContentType:   "application/merge-patch+json",
+			expectedPatch:      q.Patch{Type: q.PatchMerge, Body: []byte(`{"title":"New Title"}`), Version: q.AnyVersion},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			scenario:           "Given a json patch and an etag it should pass both on",
+			givenContentType:   "application/json-patch+json",
+			givenIfMatch:       `"3"`,
+			expectedPatch:      q.Patch{Type: q.PatchJSON, Body: []byte(`{"title":"New Title"}`), Version: 3},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			scenario:           "Given an invalid patch it should return 400",
+			givenContentType:   "application/merge-patch+json",
+			expectedPatch:      q.Patch{Type: q.PatchMerge, Body: []byte(`{"title":"New Title"}`), Version: q.AnyVersion},
+			mockErr:            q.ErrInvalidPatch,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			scenario:           "Given a failing test operation it should return 409",
+			givenContentType:   "application/json-patch+json",
+			expectedPatch:      q.Patch{Type: q.PatchJSON, Body: []byte(`{"title":"New Title"}`), Version: q.AnyVersion},
+			mockErr:            q.ErrConflict,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			scenario:           "Given a plain json body it should return 415",
+			givenContentType:   "application/json",
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			if tC.expectedPatch.Type != "" {
+				mockService.EXPECT().
+					Patch(gomock.Any(), "1", tC.expectedPatch).
+					Return(&q.Algorithm{ID: "1", Title: "New Title", Version: 4}, tC.mockErr)
+			}
+
+			req, _ := http.NewRequest(http.MethodPatch, srv.URL+"/questions/1", strings.NewReader(`{"title":"New Title"}`))
+			req.Header.Set("Content-Type", tC.givenContentType)
+			if tC.givenIfMatch != "" {
+				req.Header.Set("If-Match", tC.givenIfMatch)
+			}
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tC.expectedStatusCode, res.StatusCode)
+			if tC.expectedStatusCode == http.StatusOK {
+				var actual q.QuestionRes
+				assert.Nil(t, json.NewDecoder(res.Body).Decode(&actual))
+				assert.Equal(t, "New Title", actual.Title)
+				assert.Equal(t, `"4"`, res.Header.Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestPatchQuestion_BodyTooLarge_Return413(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	mockService.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	large := `{"content":"` + strings.Repeat("a", 16<<20) + `"}`
+	testCases := []struct {
+		scenario string
+		body     io.Reader
+	}{
+		{scenario: "with content length", body: strings.NewReader(large)},
+		{scenario: "chunked", body: io.MultiReader(strings.NewReader(large))},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPatch, srv.URL+"/questions/1", tC.body)
+			req.Header.Set(echo.HeaderContentType, "application/merge-patch+json")
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+		})
+	}
+}
+
+func TestConditionalWrites_UnknownETag_Return412(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	mockService.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	for _, etag := range []string{"3", `"three"`, `"-1"`} {
+		req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/questions/1", nil)
+		req.Header.Set("If-Match", etag)
+		res, err := http.DefaultClient.Do(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode, etag)
+	}
+}
+
+func TestRevisionEndpoints(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	createdAt := time.Date(2021, 11, 2, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().Revisions(gomock.Any(), "1").
+		Return([]q.Revision{{Number: 2, Author: "ada", CreatedAt: createdAt, Fields: []q.Field{q.FieldTitle}}}, nil)
+	mockService.EXPECT().Revision(gomock.Any(), "1", 2).
+		Return(&q.Revision{Number: 2, Author: "ada", CreatedAt: createdAt, Diff: []byte(`{"title":"Two Sum"}`), Question: q.Algorithm{Title: "Two Sum"}}, nil)
+	mockService.EXPECT().Restore(gomock.Any(), "1", 2, 5).Return(&q.Algorithm{ID: "1", Title: "Two Sum", Version: 6}, nil)
+
+	testCases := []struct {
+		scenario           string
+		method             string
+		path               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			scenario:           "Given a question it should list its revisions without diffs",
+			method:             http.MethodGet,
+			path:               "/questions/1/revisions",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"revisions":[{"number":2,"author":"ada","createdAt":"2021-11-02T00:00:00Z","fields":["title"]}]}`,
+		},
+		{
+			scenario:           "Given a revision number it should return the diff and the question",
+			method:             http.MethodGet,
+			path:               "/questions/1/revisions/2",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"number":2,"author":"ada","createdAt":"2021-11-02T00:00:00Z","fields":[],"diff":{"title":"Two Sum"},
+				"question":{"slug":"","title":"Two Sum","difficulty":"","content":"","testCases":[],"editorial":{"explanation":""}}}`,
+		},
+		{
+			scenario:           "Given an invalid revision number it should return 400",
+			method:             http.MethodGet,
+			path:               "/questions/1/revisions/two",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			scenario:           "Given restore it should return the restored question",
+			method:             http.MethodPost,
+			path:               "/questions/1/revisions/2:restore",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			scenario:           "Given an unknown custom method it should return 404",
+			method:             http.MethodPost,
+			path:               "/questions/1/revisions/2:undo",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			req, _ := http.NewRequest(tC.method, srv.URL+tC.path, nil)
+			req.Header.Set("If-Match", `"5"`)
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tC.expectedStatusCode, res.StatusCode)
+			if tC.expectedBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tC.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	testCases := []struct {
+		scenario           string
+		body               string
+		mockErr            error
+		expectedStatusCode int
+		expectedETag       string
+	}{
+		{
+			scenario:           "Given an allowed status change it should return the question with its new version",
+			body:               `{"status":"in_review"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedETag:       `"5"`,
+		},
+		{
+			scenario:           "Given a status change the workflow does not allow it should return 409",
+			body:               `{"status":"in_review"}`,
+			mockErr:            fmt.Errorf("%w: archived to in_review", q.ErrInvalidTransition),
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			scenario:           "Given a user without the role of the change it should return 403",
+			body:               `{"status":"published"}`,
+			mockErr:            q.ErrForbidden,
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			var status q.StatusReq
+			_ = json.Unmarshal([]byte(tC.body), &status)
+			var res *q.Algorithm
+			if tC.mockErr == nil {
+				res = &q.Algorithm{ID: "1", Status: q.Status(status.Status), Version: 5}
+			}
+			mockService.EXPECT().SetStatus(gomock.Any(), "1", q.Status(status.Status), 4).Return(res, tC.mockErr)
+
+			req, _ := http.NewRequest(http.MethodPut, srv.URL+"/questions/1/status", strings.NewReader(tC.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", `"4"`)
+			resp, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tC.expectedStatusCode, resp.StatusCode)
+			assert.Equal(t, tC.expectedETag, resp.Header.Get("ETag"))
+		})
+	}
+}
+
+func TestTrashEndpoints(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	deletedAt := time.Date(2021, 11, 3, 0, 0, 0, 0, time.UTC)
+	mockService.EXPECT().Trash(gomock.Any(), q.Filter{Tags: []string{"array"}}).
+		Return(&q.Page{Items: []q.Algorithm{{ID: "1", Title: "Two Sum", Version: 2, DeletedAt: deletedAt}}}, nil)
+	mockService.EXPECT().Undelete(gomock.Any(), "1").Return(&q.Algorithm{ID: "1", Title: "Two Sum", Version: 2}, nil)
+	mockService.EXPECT().Get(gomock.Any(), "2").Return(&q.Algorithm{ID: "2", Version: 1}, nil)
+	mockService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&q.Algorithm{ID: "3"}, nil)
+
+	testCases := []struct {
+		scenario           string
+		method             string
+		path               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			scenario:           "Given the trash it should list the deleted questions with the time they were deleted",
+			method:             http.MethodGet,
+			path:               "/questions/trash?tag=array",
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"items":[{"id":"1","slug":"","title":"Two Sum","content":"","languages":[],"difficulty":"","status":"","tags":null,
+				"testCases":null,"editorial":{"explanation":""},"createdAt":"0001-01-01T00:00:00Z","version":2,"deletedAt":"2021-11-03T00:00:00Z"}]}`,
+		},
+		{
+			scenario:           "Given restore it should return the restored question",
+			method:             http.MethodPost,
+			path:               "/questions/1:restore",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			scenario:           "Given an unknown custom method it should return 404",
+			method:             http.MethodPost,
+			path:               "/questions/1:undo",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			scenario:           "Given a question id it should still get the question",
+			method:             http.MethodGet,
+			path:               "/questions/2",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			scenario:           "Given a new question it should still create it",
+			method:             http.MethodPost,
+			path:               "/questions",
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			scenario:           "Given an import without content type it should still reach the import",
+			method:             http.MethodPost,
+			path:               "/questions:import",
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			req, _ := http.NewRequest(tC.method, srv.URL+tC.path, nil)
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tC.expectedStatusCode, res.StatusCode)
+			if tC.expectedBody != "" {
+				body, _ := io.ReadAll(res.Body)
+				assert.JSONEq(t, tC.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestSubmitSolution(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	mockService.EXPECT().
+		Submit(gomock.Any(), "1", q.Submission{Language: q.Python, Code: "print(3)"}).
+		Return(&q.SubmissionResult{
+			Verdict: q.WrongAnswer,
+			Cases: []q.CaseResult{
+				{Verdict: q.Accepted, Output: "3\n", Duration: 12 * time.Millisecond},
+				{Verdict: q.WrongAnswer, Output: "3\n"},
+			},
+			TestCases: []q.TestCase{
+				{Input: "1 2", Output: "3"},
+				{Input: "2 2", Output: "4", Hidden: true},
+			},
+			Score:    1,
+			MaxScore: 2,
+		}, nil)
+
+	body, _ := json.Marshal(q.SubmissionReq{Language: "python", Code: "print(3)"})
+	res, err := http.Post(srv.URL+"/questions/1/submissions", "application/json", bytes.NewBuffer(body))
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	var actual q.SubmissionRes
+	assert.Nil(t, json.NewDecoder(res.Body).Decode(&actual))
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, q.SubmissionRes{
+		Verdict:  "wrong_answer",
+		Passed:   1,
+		Total:    2,
+		Score:    1,
+		MaxScore: 2,
+		Cases: []q.CaseRes{
+			{Verdict: "accepted", TimeMillis: 12, Input: "1 2", ExpectedOutput: "3", Output: "3\n"},
+			{Verdict: "wrong_answer"},
+		},
+	}, actual)
+}
+
+func TestSubmitSolution_JudgeBusy_Return503(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	mockService.EXPECT().Submit(gomock.Any(), "1", gomock.Any()).Return(nil, q.ErrJudgeBusy)
+
+	body, _ := json.Marshal(q.SubmissionReq{Language: "python", Code: "print(3)"})
+	res, err := http.Post(srv.URL+"/questions/1/submissions", "application/json", bytes.NewBuffer(body))
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+}
+
+func TestSubmitSolution_JudgeDisabled_Return501(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	mockService.EXPECT().Submit(gomock.Any(), "1", gomock.Any()).Return(nil, q.ErrJudgeDisabled)
+
+	body, _ := json.Marshal(q.SubmissionReq{Language: "python", Code: "print(3)"})
+	res, err := http.Post(srv.URL+"/questions/1/submissions", "application/json", bytes.NewBuffer(body))
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusNotImplemented, res.StatusCode)
+}
+
+func TestGetTestCases(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	mockService.EXPECT().
+		TestCases(gomock.Any(), "1").
+		DoAndReturn(func(ctx context.Context, id string) ([]q.TestCase, error) {
+			assert.Equal(t, &q.User{Name: "editor", Role: q.RoleEditor}, q.UserFrom(ctx))
+			return []q.TestCase{
+				{Input: "1 2", Output: "3", Explanation: "sums both"},
+				{Input: "2 2", Output: "4", Hidden: true, Weight: 3},
+			}, nil
+		})
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/questions/1/testcases", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer editor-token")
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	var actual q.TestCasesRes
+	assert.Nil(t, json.NewDecoder(res.Body).Decode(&actual))
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, q.TestCasesRes{TestCases: []q.TestCaseDetailRes{
+		{Input: "1 2", Output: "3", Explanation: "sums both"},
+		{Input: "2 2", Output: "4", Hidden: true, Weight: 3},
+	}}, actual)
+}
+
+func TestReplaceTestCases(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	testCases := []struct {
+		scenario           string
+		mockErr            error
+		expectedStatusCode int
+	}{
+		{scenario: "Given editor it should return 204", expectedStatusCode: http.StatusNoContent},
+		{scenario: "Given anonymous user it should return 401", mockErr: q.ErrUnauthorized, expectedStatusCode: http.StatusUnauthorized},
+		{scenario: "Given insufficient role it should return 403", mockErr: q.ErrForbidden, expectedStatusCode: http.StatusForbidden},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			mockService.EXPECT().
+				ReplaceTestCases(gomock.Any(), "1", []q.TestCase{{Input: "1 2", Output: "3", Hidden: true, Weight: 2}}).
+				Return(tC.mockErr)
+
+			body, _ := json.Marshal(q.TestCasesReq{TestCases: []q.TestCaseReq{{Input: "1 2", Output: "3", Hidden: true, Weight: 2}}})
+			req, _ := http.NewRequest(http.MethodPut, srv.URL+"/questions/1/testcases", bytes.NewBuffer(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tC.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestTestCaseEndpoints(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	tc := q.TestCase{ID: "tc-1", Input: "1 2", Output: "3", Hidden: true}
+
+	testCases := []struct {
+		scenario           string
+		method             string
+		path               string
+		body               interface{}
+		mock               func()
+		expectedStatusCode int
+	}{
+		{
+			scenario: "get a single test case",
+			method:   http.MethodGet,
+			path:     "/questions/1/testcases/tc-1",
+			mock: func() {
+				mockService.EXPECT().TestCase(gomock.Any(), "1", "tc-1").Return(&tc, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			scenario: "get an unknown test case",
+			method:   http.MethodGet,
+			path:     "/questions/1/testcases/tc-2",
+			mock: func() {
+				mockService.EXPECT().TestCase(gomock.Any(), "1", "tc-2").Return(nil, q.ErrTestCaseNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			scenario: "add a test case",
+			method:   http.MethodPost,
+			path:     "/questions/1/testcases",
+			body:     q.TestCaseReq{Input: "1 2", Output: "3", Hidden: true},
+			mock: func() {
+				mockService.EXPECT().AddTestCase(gomock.Any(), "1", q.TestCase{Input: "1 2", Output: "3", Hidden: true}).Return(&tc, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			scenario: "update a test case takes the id from the path",
+			method:   http.MethodPut,
+			path:     "/questions/1/testcases/tc-1",
+			body:     q.TestCaseReq{ID: "other", Input: "1 2", Output: "3", Hidden: true},
+			mock: func() {
+				mockService.EXPECT().UpdateTestCase(gomock.Any(), "1", tc).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			scenario: "delete a test case",
+			method:   http.MethodDelete,
+			path:     "/questions/1/testcases/tc-1",
+			mock: func() {
+				mockService.EXPECT().DeleteTestCase(gomock.Any(), "1", "tc-1").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			tC.mock()
+
+			var body bytes.Buffer
+			if tC.body != nil {
+				assert.Nil(t, json.NewEncoder(&body).Encode(tC.body))
+			}
+			req, _ := http.NewRequest(tC.method, srv.URL+tC.path, &body)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer editor-token")
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tC.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestImportQuestions(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	mockService.EXPECT().
+		Import(gomock.Any(), gomock.Len(3), q.ImportOptions{DryRun: true}).
+		DoAndReturn(func(_ context.Context, items []q.ImportItem, _ q.ImportOptions) ([]q.ImportResult, error) {
+			assert.Equal(t, []q.ImportItem{
+				{Question: &q.Algorithm{Slug: "two-sum", Title: "Two Sum", Difficulty: "easy", TestCases: []q.TestCase{{Input: "1 2", Output: "3"}}}},
+				{Question: &q.Algorithm{Title: "Broken"}},
+			}, items[:2])
+			var docErr *q.DocumentError
+			assert.ErrorAs(t, items[2].Err, &docErr)
+			assert.Nil(t, items[2].Question)
+
+			return []q.ImportResult{
+				{Index: 0, Slug: "two-sum", Action: q.ImportCreated},
+				{Index: 1, Slug: "broken", Action: q.ImportFailed, Err: &q.ValidationError{Fields: []q.FieldError{{Field: "difficulty", Message: "invalid"}}}},
+				{Index: 2, Action: q.ImportFailed, Err: items[2].Err},
+			}, nil
+		})
+
+	body := "slug: two-sum\ntitle: Two Sum\ndifficulty: easy\ntestCases:\n- {input: 1 2, output: \"3\"}\n---\ntitle: Broken\n---\ntitle: Typo\ntag: [a]\n"
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/questions:import?dryRun=true", bytes.NewBufferString(body))
+	req.Header.Set(echo.HeaderContentType, "application/yaml")
+	req.Header.Set(echo.HeaderAuthorization, "Bearer editor-token")
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	var actual q.ImportRes
+	assert.Nil(t, json.NewDecoder(res.Body).Decode(&actual))
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 1, actual.Created)
+	assert.Equal(t, 2, actual.Failed)
+	assert.Equal(t, []q.ImportItemRes{
+		{Index: 0, Slug: "two-sum", Action: "created"},
+		{Index: 1, Slug: "broken", Action: "failed", Error: "validation failed", Fields: []q.FieldErrorRes{{Field: "difficulty", Message: "invalid"}}},
+	}, actual.Items[:2])
+	assert.Equal(t, "failed", actual.Items[2].Action)
+	assert.Contains(t, actual.Items[2].Error, "decode bundle document 2")
+}
+
+func TestImportQuestions_BundleTooLarge_Return413(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	mockService.EXPECT().Import(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	large := "[" + strings.Repeat(" ", 16<<20)
+	testCases := []struct {
+		scenario string
+		body     io.Reader
+	}{
+		{scenario: "with content length", body: strings.NewReader(large)},
+		// a reader of unknown length is sent chunked
+		{scenario: "chunked", body: io.MultiReader(strings.NewReader(large))},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, srv.URL+"/questions:import", tC.body)
+			req.Header.Set(echo.HeaderContentType, "application/json")
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+		})
+	}
+}
+
+func TestImportQuestions_InvalidRequest(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	testCases := []struct {
+		scenario           string
+		contentType        string
+		query              string
+		body               string
+		expectedStatusCode int
+	}{
+		{scenario: "unsupported content type", contentType: "text/plain", body: "[]", expectedStatusCode: http.StatusUnsupportedMediaType},
+		{scenario: "malformed bundle", contentType: "application/json", body: "{", expectedStatusCode: http.StatusBadRequest},
+		{scenario: "malformed flag", contentType: "application/json", query: "?upsert=maybe", body: "[]", expectedStatusCode: http.StatusBadRequest},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, srv.URL+"/questions:import"+tC.query, bytes.NewBufferString(tC.body))
+			req.Header.Set(echo.HeaderContentType, tC.contentType)
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, tC.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestExportQuestions(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	questions := []q.Algorithm{
+		{ID: "1", Slug: "a", Title: "A", Difficulty: q.Easy, TestCases: []q.TestCase{{ID: "tc-1", Input: "1", Output: "1", Hidden: true}}},
+		{ID: "2", Slug: "b", Title: "B", Difficulty: q.Hard},
+	}
+	expected := []q.Document{q.NewDocument(&questions[0]), q.NewDocument(&questions[1])}
+
+	for _, format := range []q.Format{q.FormatJSON, q.FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			mockService.EXPECT().
+				Export(gomock.Any(), q.Filter{Tags: []string{"tree"}}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ q.Filter, fn func(*q.Algorithm) error) error {
+					for idx := range questions {
+						if err := fn(&questions[idx]); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"/questions:export?tag=tree&format="+string(format), nil)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer editor-token")
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			actual, err := q.DecodeBundle(res.Body, format)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, format.MediaType(), res.Header.Get(echo.HeaderContentType))
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestExportQuestions_Forbidden_ReturnErrorRes(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	mockService.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(q.ErrUnauthorized)
+
+	res, err := http.Get(srv.URL + "/questions:export")
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	var actual q.ErrorRes
+	assert.Nil(t, json.NewDecoder(res.Body).Decode(&actual))
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, "unauthorized", actual.Code)
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	mockService := mocks.NewMockService(gomock.NewController(t))
+	srv := createTestServerAndRegisterRoutes(mockService)
+	defer srv.Close()
+
+	validationErr := &q.ValidationError{}
+	validationErr.Add("title", "must not be empty")
+
+	testCases := []struct {
+		scenario           string
+		mockErr            error
+		expectedStatusCode int
+		expectedRes        q.ErrorRes
+	}{
+		{
+			scenario:           "Given not found error it should return 404",
+			mockErr:            fmt.Errorf("get: %w", q.ErrNotFound),
+			expectedStatusCode: http.StatusNotFound,
+			expectedRes:        q.ErrorRes{Code: "not_found", Message: "get: question not found"},
+		},
+		{
+			scenario:           "Given invalid id error it should return 400",
+			mockErr:            q.ErrInvalidID,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRes:        q.ErrorRes{Code: "bad_request", Message: "invalid question id"},
+		},
+		{
+			scenario:           "Given conflict error it should return 409",
+			mockErr:            q.ErrConflict,
+			expectedStatusCode: http.StatusConflict,
+			expectedRes:        q.ErrorRes{Code: "conflict", Message: "question conflicts with an existing one"},
+		},
+		{
+			scenario:           "Given validation error it should return 422 with failed fields",
+			mockErr:            validationErr,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedRes: q.ErrorRes{
+				Code:    "unprocessable_entity",
+				Message: "validation failed",
+				Fields:  []q.FieldErrorRes{{Field: "title", Message: "must not be empty"}},
+			},
+		},
+		{
+			scenario:           "Given unknown error it should return 500 without leaking it",
+			mockErr:            errors.New("connection refused"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedRes:        q.ErrorRes{Code: "internal_server_error", Message: "Internal Server Error"},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			mockService.EXPECT().
+				Delete(gomock.Any(), "1", q.AnyVersion).
+				Return(tC.mockErr)
+
+			req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/questions/1", nil)
+			res, err := http.DefaultClient.Do(req)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			var actual q.ErrorRes
+			assert.Nil(t, json.NewDecoder(res.Body).Decode(&actual))
+			assert.Equal(t, tC.expectedStatusCode, res.StatusCode)
+			assert.Equal(t, tC.expectedRes, actual)
+		})
+	}
+}
+
 func createTestServerAndRegisterRoutes(service *mocks.MockService) *httptest.Server {
 	e := echo.New()
+	e.HTTPErrorHandler = q.HTTPErrorHandler
+	e.Use(q.Authenticate(map[string]q.User{"editor-token": {Name: "editor", Role: q.RoleEditor}}))
 	handler := q.NewHandler(service)
 	handler.RegisterRoutes(e)
 	srv := httptest.NewServer(e)
@@ -0,0 +1,50 @@
+package question
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const _readinessTimeout = 2 * time.Second
+
+type (
+	Pinger interface {
+		Ping(ctx context.Context) error
+	}
+
+	HealthHandler struct {
+		pinger Pinger
+	}
+
+	HealthRes struct {
+		Status string `json:"status"`
+	}
+)
+
+func NewHealthHandler(pinger Pinger) *HealthHandler {
+	return &HealthHandler{pinger}
+}
+
+func (h *HealthHandler) RegisterRoutes(router *echo.Echo) {
+	router.GET("/healthz", h.Live)
+	router.GET("/readyz", h.Ready)
+}
+
+func (h *HealthHandler) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, HealthRes{"ok"})
+}
+
+func (h *HealthHandler) Ready(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), _readinessTimeout)
+	defer cancel()
+
+	if err := h.pinger.Ping(ctx); err != nil {
+		log.Printf("readiness ping: %v\n", err)
+		return c.JSON(http.StatusServiceUnavailable, HealthRes{"unavailable"})
+	}
+	return c.JSON(http.StatusOK, HealthRes{"ok"})
+}
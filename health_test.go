@@ -0,0 +1,61 @@
+package question_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	q "github.com/codigician/question"
+	"github.com/codigician/question/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLive(t *testing.T) {
+	srv := createHealthTestServer(mocks.NewMockPinger(gomock.NewController(t)))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/healthz")
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestReady(t *testing.T) {
+	mockPinger := mocks.NewMockPinger(gomock.NewController(t))
+	srv := createHealthTestServer(mockPinger)
+	defer srv.Close()
+
+	testCases := []struct {
+		scenario           string
+		mockErr            error
+		expectedStatusCode int
+	}{
+		{
+			scenario:           "Given mongo answers ping it should return 200",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			scenario:           "Given mongo does not answer ping it should return 503",
+			mockErr:            assert.AnError,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			mockPinger.EXPECT().Ping(gomock.Any()).Return(tC.mockErr)
+
+			res, err := http.Get(srv.URL + "/readyz")
+
+			assert.Nil(t, err)
+			assert.Equal(t, tC.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
+
+func createHealthTestServer(pinger q.Pinger) *httptest.Server {
+	e := echo.New()
+	q.NewHealthHandler(pinger).RegisterRoutes(e)
+	return httptest.NewServer(e)
+}
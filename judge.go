@@ -0,0 +1,104 @@
+package question
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	Language string
+	Verdict  string
+)
+
+const (
+	Go         Language = "go"
+	Python     Language = "python"
+	Java       Language = "java"
+	Cpp        Language = "cpp"
+	JavaScript Language = "js"
+)
+
+const (
+	Accepted          Verdict = "accepted"
+	WrongAnswer       Verdict = "wrong_answer"
+	RuntimeError      Verdict = "runtime_error"
+	TimeLimitExceeded Verdict = "time_limit_exceeded"
+	CompilationError  Verdict = "compilation_error"
+)
+
+const MaxCodeLength = 64 * 1024
+
+var Languages = []Language{Go, Python, Java, Cpp, JavaScript}
+
+type (
+	Judge interface {
+		// Judge runs the submission against every test case and returns one result per case in the same order.
+		Judge(ctx context.Context, sub Submission, testCases []TestCase) ([]CaseResult, error)
+	}
+
+	Submission struct {
+		Language Language
+		Code     string
+	}
+
+	CaseResult struct {
+		Verdict  Verdict
+		Output   string
+		Error    string
+		Duration time.Duration
+	}
+
+	SubmissionResult struct {
+		Verdict   Verdict
+		Cases     []CaseResult
+		TestCases []TestCase
+
+		// Score sums the points of the accepted cases out of MaxScore.
+		Score    int
+		MaxScore int
+	}
+)
+
+func (l Language) IsValid() bool {
+	for _, lang := range Languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Submission) Validate() error {
+	var verr ValidationError
+
+	if s.Language == "" {
+		verr.Add("language", "must not be empty")
+	}
+	if s.Code == "" {
+		verr.Add("code", "must not be empty")
+	} else if len(s.Code) > MaxCodeLength {
+		verr.Add("code", "must be at most %d bytes", MaxCodeLength)
+	}
+
+	return verr.Err()
+}
+
+// NewSubmissionResult pairs the case results with their test cases. The submission is accepted
+// only if every case is, otherwise it gets the verdict of the first failing case.
+func NewSubmissionResult(testCases []TestCase, cases []CaseResult) *SubmissionResult {
+	result := &SubmissionResult{Verdict: Accepted, Cases: cases, TestCases: testCases}
+	for idx, tc := range testCases {
+		result.MaxScore += tc.Points()
+		if idx < len(cases) && cases[idx].Verdict == Accepted {
+			result.Score += tc.Points()
+		}
+	}
+
+	for _, c := range cases {
+		if c.Verdict != Accepted {
+			result.Verdict = c.Verdict
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,539 @@
+// Package memory keeps questions in process memory. It behaves like the mongo repository,
+// so the server runs without a database and handlers can be tested end to end.
+package memory
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/codigician/question"
+)
+
+// the weights of the mongo text index
+const (
+	_titleWeight   = 10
+	_tagsWeight    = 5
+	_contentWeight = 1
+)
+
+type (
+	Repository struct {
+		mu        sync.RWMutex
+		questions map[string]question.Algorithm
+		revisions map[string][]question.Revision
+		seq       uint64
+		now       func() time.Time
+	}
+
+	// position is where a question falls in the sort order of a filter.
+	position struct {
+		text   string
+		number float64
+		id     string
+	}
+)
+
+func NewRepository() *Repository {
+	return &Repository{
+		questions: make(map[string]question.Algorithm),
+		revisions: make(map[string][]question.Revision),
+		now:       time.Now,
+	}
+}
+
+// Ping always succeeds, the repository is as available as the process.
+func (r *Repository) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (r *Repository) Get(ctx context.Context, id string) (*question.Algorithm, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	q, ok := r.live(id)
+	if !ok {
+		return nil, question.ErrNotFound
+	}
+	q = clone(q)
+	return &q, nil
+}
+
+func (r *Repository) FindBySlug(ctx context.Context, slug string) (*question.Algorithm, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, q := range r.questions {
+		if q.Slug != "" && q.Slug == slug && q.DeletedAt.IsZero() {
+			q = clone(q)
+			return &q, nil
+		}
+	}
+	return nil, question.ErrNotFound
+}
+
+func (r *Repository) Save(ctx context.Context, q *question.Algorithm) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.slugTaken(q.Slug, "") {
+		return "", question.ErrConflict
+	}
+
+	// ids are 24 hex digits like mongo object ids and sort in creation order like them
+	r.seq++
+	stored := clone(*q)
+	stored.ID = fmt.Sprintf("%024x", r.seq)
+	stored.CreatedAt = r.now().UTC().Truncate(time.Second)
+	stored.Version, stored.Score, stored.DeletedAt = 1, 0, time.Time{}
+	r.questions[stored.ID] = stored
+	return stored.ID, nil
+}
+
+func (r *Repository) Find(ctx context.Context, f question.Filter) (*question.Page, error) {
+	cursor, err := f.DecodeCursor()
+	if err != nil {
+		return nil, err
+	}
+	var after *position
+	if cursor != nil {
+		if after, err = cursorPosition(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	terms := searchTerms(f.Query)
+
+	r.mu.RLock()
+	var items []question.Algorithm
+	for _, q := range r.questions {
+		if q.DeletedAt.IsZero() == f.Deleted || !matches(&q, f) {
+			continue
+		}
+		if f.Query != "" {
+			if q.Score = score(&q, terms); q.Score == 0 {
+				continue
+			}
+		}
+		if after != nil && !after.less(positionOf(&q, f.Sort)) {
+			continue
+		}
+		items = append(items, clone(q))
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		return positionOf(&items[i], f.Sort).less(positionOf(&items[j], f.Sort))
+	})
+
+	// one more than asked for, to know whether there is a next page
+	if f.Limit > 0 && len(items) > f.Limit+1 {
+		items = items[:f.Limit+1]
+	}
+	return question.NewPage(items, f), nil
+}
+
+func (r *Repository) Update(ctx context.Context, id string, q *question.Algorithm, fields ...question.Field) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.live(id)
+	if !ok {
+		return question.ErrNotFound
+	}
+	if q.Version != question.AnyVersion && q.Version != existing.Version {
+		return question.ErrPreconditionFailed
+	}
+
+	stored := clone(*q)
+	if len(fields) > 0 {
+		stored = withFields(existing, stored, fields)
+	}
+	if r.slugTaken(stored.Slug, id) {
+		return question.ErrConflict
+	}
+	stored.ID, stored.CreatedAt, stored.Score, stored.DeletedAt = id, existing.CreatedAt, 0, time.Time{}
+	stored.Status = existing.Status
+	stored.Version = existing.Version + 1
+	r.questions[id] = stored
+	q.Version = stored.Version
+	return nil
+}
+
+func (r *Repository) SetStatus(ctx context.Context, id string, status question.Status, version int) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	q, ok := r.live(id)
+	if !ok {
+		return question.ErrNotFound
+	}
+	if version != question.AnyVersion && version != q.Version {
+		return question.ErrPreconditionFailed
+	}
+	q.Status = status
+	q.Version++
+	r.questions[id] = q
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id string, version int) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.live(id)
+	if !ok {
+		return question.ErrNotFound
+	}
+	if version != question.AnyVersion && version != existing.Version {
+		return question.ErrPreconditionFailed
+	}
+	existing.DeletedAt = r.now().UTC().Truncate(time.Second)
+	r.questions[id] = existing
+	return nil
+}
+
+func (r *Repository) Trashed(ctx context.Context, id string) (*question.Algorithm, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	q, ok := r.questions[id]
+	if !ok || q.DeletedAt.IsZero() {
+		return nil, question.ErrNotFound
+	}
+	q = clone(q)
+	return &q, nil
+}
+
+func (r *Repository) Undelete(ctx context.Context, id string) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	q, ok := r.questions[id]
+	if !ok || q.DeletedAt.IsZero() {
+		return question.ErrNotFound
+	}
+	q.DeletedAt = time.Time{}
+	r.questions[id] = q
+	return nil
+}
+
+func (r *Repository) Purge(ctx context.Context, before time.Time) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []string
+	for id, q := range r.questions {
+		if !q.DeletedAt.IsZero() && q.DeletedAt.Before(before) {
+			delete(r.questions, id)
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (r *Repository) AddTestCase(ctx context.Context, id string, tc question.TestCase) error {
+	return r.updateTestCases(id, func(testCases []question.TestCase) ([]question.TestCase, error) {
+		return append(testCases, tc), nil
+	})
+}
+
+func (r *Repository) UpdateTestCase(ctx context.Context, id string, tc question.TestCase) error {
+	return r.updateTestCases(id, func(testCases []question.TestCase) ([]question.TestCase, error) {
+		idx, err := indexOf(testCases, tc.ID)
+		if err != nil {
+			return nil, err
+		}
+		testCases[idx] = tc
+		return testCases, nil
+	})
+}
+
+func (r *Repository) DeleteTestCase(ctx context.Context, id, testCaseID string) error {
+	return r.updateTestCases(id, func(testCases []question.TestCase) ([]question.TestCase, error) {
+		idx, err := indexOf(testCases, testCaseID)
+		if err != nil {
+			return nil, err
+		}
+		if len(testCases) == 1 {
+			return nil, question.ErrLastTestCase
+		}
+		return append(testCases[:idx], testCases[idx+1:]...), nil
+	})
+}
+
+func (r *Repository) updateTestCases(id string, fn func([]question.TestCase) ([]question.TestCase, error)) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	q, ok := r.live(id)
+	if !ok {
+		return question.ErrNotFound
+	}
+	q = clone(q)
+
+	testCases, err := fn(q.TestCases)
+	if err != nil {
+		return err
+	}
+	q.TestCases = testCases
+	q.Version++
+	r.questions[id] = q
+	return nil
+}
+
+// live returns the question unless it is missing or in the trash, the caller holds the lock.
+func (r *Repository) live(id string) (question.Algorithm, bool) {
+	q, ok := r.questions[id]
+	return q, ok && q.DeletedAt.IsZero()
+}
+
+func (r *Repository) slugTaken(slug, exceptID string) bool {
+	if slug == "" {
+		return false
+	}
+	for id, q := range r.questions {
+		if id != exceptID && q.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// withFields copies the given fields of src over a copy of dst.
+func withFields(dst, src question.Algorithm, fields []question.Field) question.Algorithm {
+	dst = clone(dst)
+	for _, field := range fields {
+		switch field {
+		case question.FieldSlug:
+			dst.Slug = src.Slug
+		case question.FieldTitle:
+			dst.Title = src.Title
+		case question.FieldContent:
+			dst.Content = src.Content
+		case question.FieldTemplates:
+			dst.Templates = src.Templates
+		case question.FieldDifficulty:
+			dst.Difficulty = src.Difficulty
+		case question.FieldTags:
+			dst.Tags = src.Tags
+		case question.FieldTestCases:
+			dst.TestCases = src.TestCases
+		case question.FieldEditorial:
+			dst.Editorial = src.Editorial
+		}
+	}
+	return dst
+}
+
+func indexOf(testCases []question.TestCase, testCaseID string) (int, error) {
+	for idx, tc := range testCases {
+		if tc.ID == testCaseID {
+			return idx, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", question.ErrTestCaseNotFound, testCaseID)
+}
+
+func checkID(id string) error {
+	if _, err := hex.DecodeString(id); err != nil || len(id) != 24 {
+		return fmt.Errorf("%w: %q", question.ErrInvalidID, id)
+	}
+	return nil
+}
+
+func matches(q *question.Algorithm, f question.Filter) bool {
+	tags := make(map[string]bool, len(q.Tags))
+	for _, tag := range q.Tags {
+		tags[tag] = true
+	}
+
+	if len(f.Tags) > 0 {
+		found := 0
+		for _, tag := range f.Tags {
+			if tags[tag] {
+				found++
+			}
+		}
+		switch f.TagMatch {
+		case question.TagMatchAll:
+			if found < len(f.Tags) {
+				return false
+			}
+		case question.TagMatchNone:
+			if found > 0 {
+				return false
+			}
+		default:
+			if found == 0 {
+				return false
+			}
+		}
+	}
+	for _, tag := range f.ExcludeTags {
+		if tags[tag] {
+			return false
+		}
+	}
+
+	if len(f.Statuses) > 0 && !containsStatus(f.Statuses, q.Status) {
+		return false
+	}
+
+	if len(f.Difficulties) > 0 {
+		for _, d := range f.Difficulties {
+			if q.Difficulty == d {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsStatus(statuses []question.Status, status question.Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// searchTerms splits a query into lowercase words, words prefixed with - exclude questions.
+func searchTerms(query string) []string {
+	return strings.Fields(strings.ToLower(strings.ReplaceAll(query, `"`, " ")))
+}
+
+// score weighs every occurrence of a search term like the mongo text index does: title 10,
+// tags 5 and content 1. Unlike mongo words are not stemmed, so the scores only rank alike.
+func score(q *question.Algorithm, terms []string) float64 {
+	fields := []struct {
+		words  map[string]int
+		weight float64
+	}{
+		{wordCounts(q.Title), _titleWeight},
+		{wordCounts(strings.Join(q.Tags, " ")), _tagsWeight},
+		{wordCounts(q.Content), _contentWeight},
+	}
+
+	var total float64
+	for _, term := range terms {
+		negated := strings.HasPrefix(term, "-")
+		term = strings.TrimPrefix(term, "-")
+		for _, field := range fields {
+			if field.words[term] == 0 {
+				continue
+			}
+			if negated {
+				return 0
+			}
+			total += field.weight * float64(field.words[term])
+		}
+	}
+	return total
+}
+
+func wordCounts(s string) map[string]int {
+	counts := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		counts[word]++
+	}
+	return counts
+}
+
+func positionOf(q *question.Algorithm, sort question.SortKey) position {
+	switch sort {
+	case question.SortTitle:
+		return position{text: q.Title, id: q.ID}
+	case question.SortDifficulty:
+		return position{number: float64(q.Difficulty.Level()), id: q.ID}
+	case question.SortRelevance:
+		// best matches first
+		return position{number: -q.Score, id: q.ID}
+	default:
+		return position{id: q.ID}
+	}
+}
+
+func cursorPosition(c *question.Cursor) (*position, error) {
+	if checkID(c.ID) != nil {
+		return nil, question.ErrInvalidCursor
+	}
+
+	p := &position{id: c.ID}
+	var err error
+	switch c.Sort {
+	case question.SortTitle:
+		p.text = c.Value
+	case question.SortDifficulty, question.SortRelevance:
+		p.number, err = strconv.ParseFloat(c.Value, 64)
+		if c.Sort == question.SortRelevance {
+			p.number = -p.number
+		}
+	}
+	if err != nil {
+		return nil, question.ErrInvalidCursor
+	}
+	return p, nil
+}
+
+func (p position) less(o position) bool {
+	if p.text != o.text {
+		return p.text < o.text
+	}
+	if p.number != o.number {
+		return p.number < o.number
+	}
+	return p.id < o.id
+}
+
+func clone(q question.Algorithm) question.Algorithm {
+	if q.Templates != nil {
+		templates := make(map[question.Language]string, len(q.Templates))
+		for lang, code := range q.Templates {
+			templates[lang] = code
+		}
+		q.Templates = templates
+	}
+	q.Tags = append([]string(nil), q.Tags...)
+	q.TestCases = append([]question.TestCase(nil), q.TestCases...)
+	return q
+}
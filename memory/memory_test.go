@@ -0,0 +1,48 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/codigician/question/memory"
+	"github.com/codigician/question/repositorytest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) question.Repository {
+		return memory.NewRepository()
+	})
+}
+
+func TestRevisionRepositoryConformance(t *testing.T) {
+	repositorytest.RunRevisions(t, func(t *testing.T) question.RevisionRepository {
+		return memory.NewRepository()
+	})
+}
+
+func TestRepository_StoredQuestionsAreNotShared(t *testing.T) {
+	ctx := context.Background()
+	r := memory.NewRepository()
+
+	given := repositorytest.NewQuestion("two-sum", question.Easy, "array")
+	id, _ := r.Save(ctx, given)
+	given.Tags[0] = "changed by caller"
+
+	got, _ := r.Get(ctx, id)
+	got.TestCases[0].Output = "changed by reader"
+	got.Templates[question.Go] = "changed by reader"
+
+	actual, err := r.Get(ctx, id)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"array"}, actual.Tags)
+	assert.Equal(t, "3", actual.TestCases[0].Output)
+	assert.Equal(t, "package main", actual.Templates[question.Go])
+}
+
+func TestRepository_InvalidID_ReturnErrInvalidID(t *testing.T) {
+	_, err := memory.NewRepository().Get(context.Background(), "not-an-id")
+
+	assert.ErrorIs(t, err, question.ErrInvalidID)
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/codigician/question"
+)
+
+func (r *Repository) SaveRevision(ctx context.Context, rev *question.Revision) error {
+	if err := checkID(rev.QuestionID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.revisions[rev.QuestionID] {
+		if existing.Number == rev.Number {
+			return question.ErrConflict
+		}
+	}
+
+	stored := cloneRevision(*rev)
+	stored.CreatedAt = r.now().UTC().Truncate(time.Second)
+	r.revisions[rev.QuestionID] = append(r.revisions[rev.QuestionID], stored)
+	return nil
+}
+
+func (r *Repository) FindRevisions(ctx context.Context, questionID string) ([]question.Revision, error) {
+	if err := checkID(questionID); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := make([]question.Revision, 0, len(r.revisions[questionID]))
+	for _, rev := range r.revisions[questionID] {
+		rev = cloneRevision(rev)
+		rev.Question, rev.Diff = question.Algorithm{}, nil
+		revisions = append(revisions, rev)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Number > revisions[j].Number })
+	return revisions, nil
+}
+
+func (r *Repository) GetRevision(ctx context.Context, questionID string, number int) (*question.Revision, error) {
+	return r.findRevision(questionID, func(rev *question.Revision) bool { return rev.Number == number })
+}
+
+func (r *Repository) LatestRevision(ctx context.Context, questionID string) (*question.Revision, error) {
+	var latest int
+	return r.findRevision(questionID, func(rev *question.Revision) bool {
+		if rev.Number > latest {
+			latest = rev.Number
+			return true
+		}
+		return false
+	})
+}
+
+func (r *Repository) DeleteRevisions(ctx context.Context, questionID string) error {
+	if err := checkID(questionID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.revisions, questionID)
+	return nil
+}
+
+// findRevision returns the last revision matching fn, in the order they were saved.
+func (r *Repository) findRevision(questionID string, fn func(rev *question.Revision) bool) (*question.Revision, error) {
+	if err := checkID(questionID); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found *question.Revision
+	for idx := range r.revisions[questionID] {
+		if rev := &r.revisions[questionID][idx]; fn(rev) {
+			found = rev
+		}
+	}
+	if found == nil {
+		return nil, question.ErrRevisionNotFound
+	}
+	rev := cloneRevision(*found)
+	return &rev, nil
+}
+
+func cloneRevision(rev question.Revision) question.Revision {
+	rev.Fields = append([]question.Field(nil), rev.Fields...)
+	rev.Diff = append([]byte(nil), rev.Diff...)
+	rev.Question = clone(rev.Question)
+	return rev
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: judge.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	question "github.com/codigician/question"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockJudge is a mock of Judge interface.
+type MockJudge struct {
+	ctrl     *gomock.Controller
+	recorder *MockJudgeMockRecorder
+}
+
+// MockJudgeMockRecorder is the mock recorder for MockJudge.
+type MockJudgeMockRecorder struct {
+	mock *MockJudge
+}
+
+// NewMockJudge creates a new mock instance.
+func NewMockJudge(ctrl *gomock.Controller) *MockJudge {
+	mock := &MockJudge{ctrl: ctrl}
+	mock.recorder = &MockJudgeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJudge) EXPECT() *MockJudgeMockRecorder {
+	return m.recorder
+}
+
+// Judge mocks base method.
+func (m *MockJudge) Judge(ctx context.Context, sub question.Submission, testCases []question.TestCase) ([]question.CaseResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Judge", ctx, sub, testCases)
+	ret0, _ := ret[0].([]question.CaseResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Judge indicates an expected call of Judge.
+func (mr *MockJudgeMockRecorder) Judge(ctx, sub, testCases interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Judge", reflect.TypeOf((*MockJudge)(nil).Judge), ctx, sub, testCases)
+}
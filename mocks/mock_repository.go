@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	question "github.com/codigician/question"
 	gomock "github.com/golang/mock/gomock"
@@ -35,33 +36,76 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddTestCase mocks base method.
+func (m *MockRepository) AddTestCase(ctx context.Context, id string, tc question.TestCase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTestCase", ctx, id, tc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTestCase indicates an expected call of AddTestCase.
+func (mr *MockRepositoryMockRecorder) AddTestCase(ctx, id, tc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTestCase", reflect.TypeOf((*MockRepository)(nil).AddTestCase), ctx, id, tc)
+}
+
 // Delete mocks base method.
-func (m *MockRepository) Delete(ctx context.Context, id string) error {
+func (m *MockRepository) Delete(ctx context.Context, id string, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id, version)
+}
+
+// DeleteTestCase mocks base method.
+func (m *MockRepository) DeleteTestCase(ctx context.Context, id, testCaseID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTestCase", ctx, id, testCaseID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTestCase indicates an expected call of DeleteTestCase.
+func (mr *MockRepositoryMockRecorder) DeleteTestCase(ctx, id, testCaseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTestCase", reflect.TypeOf((*MockRepository)(nil).DeleteTestCase), ctx, id, testCaseID)
 }
 
 // Find mocks base method.
-func (m *MockRepository) Find(ctx context.Context, tags []string, difficulty question.Difficulty) ([]question.Algorithm, error) {
+func (m *MockRepository) Find(ctx context.Context, f question.Filter) (*question.Page, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Find", ctx, tags, difficulty)
-	ret0, _ := ret[0].([]question.Algorithm)
+	ret := m.ctrl.Call(m, "Find", ctx, f)
+	ret0, _ := ret[0].(*question.Page)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Find indicates an expected call of Find.
-func (mr *MockRepositoryMockRecorder) Find(ctx, tags, difficulty interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Find(ctx, f interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockRepository)(nil).Find), ctx, tags, difficulty)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockRepository)(nil).Find), ctx, f)
+}
+
+// FindBySlug mocks base method.
+func (m *MockRepository) FindBySlug(ctx context.Context, slug string) (*question.Algorithm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySlug", ctx, slug)
+	ret0, _ := ret[0].(*question.Algorithm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySlug indicates an expected call of FindBySlug.
+func (mr *MockRepositoryMockRecorder) FindBySlug(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySlug", reflect.TypeOf((*MockRepository)(nil).FindBySlug), ctx, slug)
 }
 
 // Get mocks base method.
@@ -79,6 +123,21 @@ func (mr *MockRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepository)(nil).Get), ctx, id)
 }
 
+// Purge mocks base method.
+func (m *MockRepository) Purge(ctx context.Context, before time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockRepositoryMockRecorder) Purge(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockRepository)(nil).Purge), ctx, before)
+}
+
 // Save mocks base method.
 func (m *MockRepository) Save(ctx context.Context, q *question.Algorithm) (string, error) {
 	m.ctrl.T.Helper()
@@ -94,16 +153,78 @@ func (mr *MockRepositoryMockRecorder) Save(ctx, q interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepository)(nil).Save), ctx, q)
 }
 
+// SetStatus mocks base method.
+func (m *MockRepository) SetStatus(ctx context.Context, id string, status question.Status, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", ctx, id, status, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockRepositoryMockRecorder) SetStatus(ctx, id, status, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockRepository)(nil).SetStatus), ctx, id, status, version)
+}
+
+// Trashed mocks base method.
+func (m *MockRepository) Trashed(ctx context.Context, id string) (*question.Algorithm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trashed", ctx, id)
+	ret0, _ := ret[0].(*question.Algorithm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Trashed indicates an expected call of Trashed.
+func (mr *MockRepositoryMockRecorder) Trashed(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trashed", reflect.TypeOf((*MockRepository)(nil).Trashed), ctx, id)
+}
+
+// Undelete mocks base method.
+func (m *MockRepository) Undelete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Undelete indicates an expected call of Undelete.
+func (mr *MockRepositoryMockRecorder) Undelete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockRepository)(nil).Undelete), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockRepository) Update(ctx context.Context, id string, q *question.Algorithm) error {
+func (m *MockRepository) Update(ctx context.Context, id string, q *question.Algorithm, fields ...question.Field) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, q)
+	varargs := []interface{}{ctx, id, q}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Update", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRepositoryMockRecorder) Update(ctx, id, q interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(ctx, id, q interface{}, fields ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, id, q}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), varargs...)
+}
+
+// UpdateTestCase mocks base method.
+func (m *MockRepository) UpdateTestCase(ctx context.Context, id string, tc question.TestCase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTestCase", ctx, id, tc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTestCase indicates an expected call of UpdateTestCase.
+func (mr *MockRepositoryMockRecorder) UpdateTestCase(ctx, id, tc interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, id, q)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTestCase", reflect.TypeOf((*MockRepository)(nil).UpdateTestCase), ctx, id, tc)
 }
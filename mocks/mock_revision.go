@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: revision.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	question "github.com/codigician/question"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRevisionRepository is a mock of RevisionRepository interface.
+type MockRevisionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionRepositoryMockRecorder
+}
+
+// MockRevisionRepositoryMockRecorder is the mock recorder for MockRevisionRepository.
+type MockRevisionRepositoryMockRecorder struct {
+	mock *MockRevisionRepository
+}
+
+// NewMockRevisionRepository creates a new mock instance.
+func NewMockRevisionRepository(ctrl *gomock.Controller) *MockRevisionRepository {
+	mock := &MockRevisionRepository{ctrl: ctrl}
+	mock.recorder = &MockRevisionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevisionRepository) EXPECT() *MockRevisionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteRevisions mocks base method.
+func (m *MockRevisionRepository) DeleteRevisions(ctx context.Context, questionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRevisions", ctx, questionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRevisions indicates an expected call of DeleteRevisions.
+func (mr *MockRevisionRepositoryMockRecorder) DeleteRevisions(ctx, questionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRevisions", reflect.TypeOf((*MockRevisionRepository)(nil).DeleteRevisions), ctx, questionID)
+}
+
+// FindRevisions mocks base method.
+func (m *MockRevisionRepository) FindRevisions(ctx context.Context, questionID string) ([]question.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRevisions", ctx, questionID)
+	ret0, _ := ret[0].([]question.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRevisions indicates an expected call of FindRevisions.
+func (mr *MockRevisionRepositoryMockRecorder) FindRevisions(ctx, questionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRevisions", reflect.TypeOf((*MockRevisionRepository)(nil).FindRevisions), ctx, questionID)
+}
+
+// GetRevision mocks base method.
+func (m *MockRevisionRepository) GetRevision(ctx context.Context, questionID string, number int) (*question.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, questionID, number)
+	ret0, _ := ret[0].(*question.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockRevisionRepositoryMockRecorder) GetRevision(ctx, questionID, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockRevisionRepository)(nil).GetRevision), ctx, questionID, number)
+}
+
+// LatestRevision mocks base method.
+func (m *MockRevisionRepository) LatestRevision(ctx context.Context, questionID string) (*question.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestRevision", ctx, questionID)
+	ret0, _ := ret[0].(*question.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestRevision indicates an expected call of LatestRevision.
+func (mr *MockRevisionRepositoryMockRecorder) LatestRevision(ctx, questionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestRevision", reflect.TypeOf((*MockRevisionRepository)(nil).LatestRevision), ctx, questionID)
+}
+
+// SaveRevision mocks base method.
+func (m *MockRevisionRepository) SaveRevision(ctx context.Context, rev *question.Revision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRevision", ctx, rev)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRevision indicates an expected call of SaveRevision.
+func (mr *MockRevisionRepositoryMockRecorder) SaveRevision(ctx, rev interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRevision", reflect.TypeOf((*MockRevisionRepository)(nil).SaveRevision), ctx, rev)
+}
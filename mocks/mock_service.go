@@ -35,6 +35,21 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// AddTestCase mocks base method.
+func (m *MockService) AddTestCase(ctx context.Context, id string, tc question.TestCase) (*question.TestCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTestCase", ctx, id, tc)
+	ret0, _ := ret[0].(*question.TestCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTestCase indicates an expected call of AddTestCase.
+func (mr *MockServiceMockRecorder) AddTestCase(ctx, id, tc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTestCase", reflect.TypeOf((*MockService)(nil).AddTestCase), ctx, id, tc)
+}
+
 // Create mocks base method.
 func (m *MockService) Create(ctx context.Context, q *question.Algorithm) (*question.Algorithm, error) {
 	m.ctrl.T.Helper()
@@ -51,24 +66,52 @@ func (mr *MockServiceMockRecorder) Create(ctx, q interface{}) *gomock.Call {
 }
 
 // Delete mocks base method.
-func (m *MockService) Delete(ctx context.Context, id string) error {
+func (m *MockService) Delete(ctx context.Context, id string, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Delete(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), ctx, id, version)
+}
+
+// DeleteTestCase mocks base method.
+func (m *MockService) DeleteTestCase(ctx context.Context, id, testCaseID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTestCase", ctx, id, testCaseID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTestCase indicates an expected call of DeleteTestCase.
+func (mr *MockServiceMockRecorder) DeleteTestCase(ctx, id, testCaseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTestCase", reflect.TypeOf((*MockService)(nil).DeleteTestCase), ctx, id, testCaseID)
+}
+
+// Export mocks base method.
+func (m *MockService) Export(ctx context.Context, f question.Filter, fn func(*question.Algorithm) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, f, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockServiceMockRecorder) Export(ctx, f, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockService)(nil).Export), ctx, f, fn)
 }
 
 // Filter mocks base method.
-func (m *MockService) Filter(ctx context.Context, f question.Filter) ([]question.Algorithm, error) {
+func (m *MockService) Filter(ctx context.Context, f question.Filter) (*question.Page, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Filter", ctx, f)
-	ret0, _ := ret[0].([]question.Algorithm)
+	ret0, _ := ret[0].(*question.Page)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -94,6 +137,185 @@ func (mr *MockServiceMockRecorder) Get(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockService)(nil).Get), ctx, id)
 }
 
+// Import mocks base method.
+func (m *MockService) Import(ctx context.Context, items []question.ImportItem, opts question.ImportOptions) ([]question.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, items, opts)
+	ret0, _ := ret[0].([]question.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockServiceMockRecorder) Import(ctx, items, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockService)(nil).Import), ctx, items, opts)
+}
+
+// Patch mocks base method.
+func (m *MockService) Patch(ctx context.Context, id string, p question.Patch) (*question.Algorithm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, id, p)
+	ret0, _ := ret[0].(*question.Algorithm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockServiceMockRecorder) Patch(ctx, id, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockService)(nil).Patch), ctx, id, p)
+}
+
+// ReplaceTestCases mocks base method.
+func (m *MockService) ReplaceTestCases(ctx context.Context, id string, testCases []question.TestCase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceTestCases", ctx, id, testCases)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceTestCases indicates an expected call of ReplaceTestCases.
+func (mr *MockServiceMockRecorder) ReplaceTestCases(ctx, id, testCases interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceTestCases", reflect.TypeOf((*MockService)(nil).ReplaceTestCases), ctx, id, testCases)
+}
+
+// Restore mocks base method.
+func (m *MockService) Restore(ctx context.Context, id string, number, version int) (*question.Algorithm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id, number, version)
+	ret0, _ := ret[0].(*question.Algorithm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockServiceMockRecorder) Restore(ctx, id, number, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService)(nil).Restore), ctx, id, number, version)
+}
+
+// Revision mocks base method.
+func (m *MockService) Revision(ctx context.Context, id string, number int) (*question.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revision", ctx, id, number)
+	ret0, _ := ret[0].(*question.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revision indicates an expected call of Revision.
+func (mr *MockServiceMockRecorder) Revision(ctx, id, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revision", reflect.TypeOf((*MockService)(nil).Revision), ctx, id, number)
+}
+
+// Revisions mocks base method.
+func (m *MockService) Revisions(ctx context.Context, id string) ([]question.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revisions", ctx, id)
+	ret0, _ := ret[0].([]question.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revisions indicates an expected call of Revisions.
+func (mr *MockServiceMockRecorder) Revisions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revisions", reflect.TypeOf((*MockService)(nil).Revisions), ctx, id)
+}
+
+// SetStatus mocks base method.
+func (m *MockService) SetStatus(ctx context.Context, id string, status question.Status, version int) (*question.Algorithm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", ctx, id, status, version)
+	ret0, _ := ret[0].(*question.Algorithm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockServiceMockRecorder) SetStatus(ctx, id, status, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockService)(nil).SetStatus), ctx, id, status, version)
+}
+
+// Submit mocks base method.
+func (m *MockService) Submit(ctx context.Context, id string, sub question.Submission) (*question.SubmissionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", ctx, id, sub)
+	ret0, _ := ret[0].(*question.SubmissionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockServiceMockRecorder) Submit(ctx, id, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockService)(nil).Submit), ctx, id, sub)
+}
+
+// TestCase mocks base method.
+func (m *MockService) TestCase(ctx context.Context, id, testCaseID string) (*question.TestCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TestCase", ctx, id, testCaseID)
+	ret0, _ := ret[0].(*question.TestCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestCase indicates an expected call of TestCase.
+func (mr *MockServiceMockRecorder) TestCase(ctx, id, testCaseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestCase", reflect.TypeOf((*MockService)(nil).TestCase), ctx, id, testCaseID)
+}
+
+// TestCases mocks base method.
+func (m *MockService) TestCases(ctx context.Context, id string) ([]question.TestCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TestCases", ctx, id)
+	ret0, _ := ret[0].([]question.TestCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestCases indicates an expected call of TestCases.
+func (mr *MockServiceMockRecorder) TestCases(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestCases", reflect.TypeOf((*MockService)(nil).TestCases), ctx, id)
+}
+
+// Trash mocks base method.
+func (m *MockService) Trash(ctx context.Context, f question.Filter) (*question.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trash", ctx, f)
+	ret0, _ := ret[0].(*question.Page)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Trash indicates an expected call of Trash.
+func (mr *MockServiceMockRecorder) Trash(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trash", reflect.TypeOf((*MockService)(nil).Trash), ctx, f)
+}
+
+// Undelete mocks base method.
+func (m *MockService) Undelete(ctx context.Context, id string) (*question.Algorithm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret0, _ := ret[0].(*question.Algorithm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undelete indicates an expected call of Undelete.
+func (mr *MockServiceMockRecorder) Undelete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockService)(nil).Undelete), ctx, id)
+}
+
 // Update mocks base method.
 func (m *MockService) Update(ctx context.Context, id string, q *question.Algorithm) error {
 	m.ctrl.T.Helper()
@@ -107,3 +329,17 @@ func (mr *MockServiceMockRecorder) Update(ctx, id, q interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, id, q)
 }
+
+// UpdateTestCase mocks base method.
+func (m *MockService) UpdateTestCase(ctx context.Context, id string, tc question.TestCase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTestCase", ctx, id, tc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTestCase indicates an expected call of UpdateTestCase.
+func (mr *MockServiceMockRecorder) UpdateTestCase(ctx, id, tc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTestCase", reflect.TypeOf((*MockService)(nil).UpdateTestCase), ctx, id, tc)
+}
@@ -0,0 +1,119 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	_indexID             = "_id_"
+	_indexTags           = "tags"
+	_indexDifficulty     = "difficulty"
+	_indexTagsDifficulty = "tags_difficulty"
+	_indexTextSearch     = "text_search"
+	_indexSlug           = "slug_unique"
+	_indexDeletedAt      = "deleted_at"
+	_indexStatus         = "status"
+
+	_indexRevisionNumber = "question_number_unique"
+)
+
+// EnsureIndexes creates the indexes the repository relies on. Indexes are named,
+// so running it again against an up to date collection is a no-op.
+func (m *Mongo) EnsureIndexes(ctx context.Context) error {
+	if _, err := m.lq().Indexes().CreateMany(ctx, indexModels()); err != nil {
+		return err
+	}
+
+	// a revision number is recorded once per question, racing writers find out through it
+	_, err := m.lr().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "questionId", Value: 1}, {Key: "number", Value: -1}},
+		Options: options.Index().SetName(_indexRevisionNumber).SetUnique(true),
+	})
+	return err
+}
+
+func (m *Mongo) ListIndexes(ctx context.Context) ([]string, error) {
+	specs, err := m.lq().Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		names = append(names, spec.Name)
+	}
+	return names, nil
+}
+
+// DropStaleIndexes drops every index EnsureIndexes would not create and returns their names.
+func (m *Mongo) DropStaleIndexes(ctx context.Context) ([]string, error) {
+	names, err := m.ListIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := map[string]bool{_indexID: true}
+	for _, model := range indexModels() {
+		wanted[*model.Options.Name] = true
+	}
+
+	var dropped []string
+	for _, name := range names {
+		if wanted[name] {
+			continue
+		}
+		if _, err := m.lq().Indexes().DropOne(ctx, name); err != nil {
+			return dropped, err
+		}
+		dropped = append(dropped, name)
+	}
+	return dropped, nil
+}
+
+func indexModels() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tags", Value: 1}},
+			Options: options.Index().SetName(_indexTags),
+		},
+		{
+			Keys:    bson.D{{Key: "difficulty", Value: 1}},
+			Options: options.Index().SetName(_indexDifficulty),
+		},
+		{
+			Keys:    bson.D{{Key: "tags", Value: 1}, {Key: "difficulty", Value: 1}},
+			Options: options.Index().SetName(_indexTagsDifficulty),
+		},
+		{
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "content", Value: "text"},
+				{Key: "tags", Value: "text"},
+			},
+			Options: options.Index().
+				SetName(_indexTextSearch).
+				SetWeights(bson.M{"title": 10, "tags": 5, "content": 1}),
+		},
+		{
+			// questions created before slugs existed have none, they must not collide with each other
+			Keys: bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().
+				SetName(_indexSlug).
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"slug": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}},
+			Options: options.Index().SetName(_indexStatus),
+		},
+		{
+			// only questions in the trash have one, purging looks them up by it
+			Keys:    bson.D{{Key: "deletedAt", Value: 1}},
+			Options: options.Index().SetName(_indexDeletedAt).SetSparse(true),
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/codigician/question"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type migration struct {
+	name   string
+	filter bson.M
+	update interface{}
+}
+
+// Migrate rewrites the questions stored by older versions to the current schema. Every migration
+// only matches the questions it has not rewritten yet, so running it again is a no-op.
+func (m *Mongo) Migrate(ctx context.Context) error {
+	for _, mig := range migrations() {
+		if _, err := m.lq().UpdateMany(ctx, mig.filter, mig.update); err != nil {
+			return fmt.Errorf("migrate %s: %w", mig.name, err)
+		}
+	}
+	if err := m.assignTestCaseIDs(ctx); err != nil {
+		return fmt.Errorf("migrate test case ids: %w", err)
+	}
+	return nil
+}
+
+// assignTestCaseIDs gives ids to the test cases stored before they had one. Ids cannot be made up
+// by an update, so every question is rewritten on its own and only while its test cases are still
+// the ones that were read.
+func (m *Mongo) assignTestCaseIDs(ctx context.Context) error {
+	cur, err := m.lq().Find(ctx, bson.M{"testCases": bson.M{"$elemMatch": bson.M{"id": bson.M{"$exists": false}}}})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var doc struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			TestCases bson.RawValue      `bson:"testCases"`
+		}
+		if err := cur.Decode(&doc); err != nil {
+			return err
+		}
+		var testCases TestCases
+		if err := doc.TestCases.Unmarshal(&testCases); err != nil {
+			return err
+		}
+
+		tcs := testCases.to()
+		question.AssignTestCaseIDs(tcs)
+		filter := bson.M{"_id": doc.ID, "testCases": doc.TestCases}
+		if _, err := m.lq().UpdateOne(ctx, filter, bson.M{"$set": bson.M{"testCases": fromTestCases(tcs)}}); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+func migrations() []migration {
+	return []migration{
+		{
+			// without it the first update would $inc the version to 1, the version it was read as
+			name:   "version",
+			filter: bson.M{"version": bson.M{"$in": bson.A{nil, 0}}},
+			update: bson.M{"$set": bson.M{"version": 1}},
+		},
+		{
+			name:   "empty template",
+			filter: bson.M{"template": bson.M{"$exists": true, "$in": bson.A{"", nil}}},
+			update: bson.M{"$unset": bson.M{"template": ""}},
+		},
+		{
+			// a template stored for the language already wins over the legacy one
+			name:   "template per language",
+			filter: bson.M{"template": bson.M{"$type": "string"}},
+			update: bson.A{
+				bson.M{"$set": bson.M{"templates": bson.M{"$mergeObjects": bson.A{
+					bson.M{string(_legacyTemplateLanguage): "$template"},
+					bson.M{"$ifNull": bson.A{"$templates", bson.M{}}},
+				}}}},
+				bson.M{"$unset": "template"},
+			},
+		},
+	}
+}
@@ -2,39 +2,106 @@ package mongo
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/codigician/question"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 const (
 	_databaseListing    = "listing"
 	_collectionQuestion = "question"
+
+	// _legacyTemplateLanguage is the language of the single template questions had before
+	// templates per language.
+	_legacyTemplateLanguage = question.Go
 )
 
 type (
 	Mongo struct {
-		uri    string
-		client *mongo.Client
+		uri        string
+		database   string
+		collection string
+		client     *mongo.Client
 	}
 
+	Option func(m *Mongo)
+
 	AlgoQuestion struct {
 		ID         primitive.ObjectID `bson:"_id"`
+		Slug       string             `bson:"slug,omitempty"`
 		Title      string             `bson:"title"`
 		Content    string             `bson:"content"`
-		Template   string             `bson:"template"`
+		Templates  map[string]string  `bson:"templates"`
 		Difficulty string             `bson:"difficulty"`
 		Tags       []string           `bson:"tags"`
+		TestCases  []TestCase         `bson:"testCases"`
+		Editorial  Editorial          `bson:"editorial"`
+
+		// Template is only set on questions stored before templates per language, until Migrate
+		// moves it to Templates.
+		Template string `bson:"template,omitempty"`
+
+		// Status is missing on questions stored before the review workflow, they count as published.
+		Status string `bson:"status,omitempty"`
+
+		// Version is missing on questions stored before versions were introduced, they are read
+		// as version 1 and migrated to it.
+		Version int `bson:"version"`
+
+		// DeletedAt is only set on questions in the trash.
+		DeletedAt *time.Time `bson:"deletedAt,omitempty"`
+
+		// Score is only filled in by text searches, it is never stored.
+		Score float64 `bson:"score,omitempty"`
+	}
+
+	TestCase struct {
+		ID          string `bson:"id,omitempty"`
+		Input       string `bson:"input"`
+		Output      string `bson:"output"`
+		Hidden      bool   `bson:"hidden"`
+		Explanation string `bson:"explanation,omitempty"`
+		Weight      int    `bson:"weight,omitempty"`
+	}
+
+	Editorial struct {
+		Explanation string `bson:"explanation"`
 	}
 
 	AlgoQuestions []AlgoQuestion
+	TestCases     []TestCase
 )
 
-func NewMongo(uri string) *Mongo {
-	return &Mongo{uri: uri}
+func NewMongo(uri string, opts ...Option) *Mongo {
+	m := &Mongo{
+		uri:        uri,
+		database:   _databaseListing,
+		collection: _collectionQuestion,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func WithDatabase(database string) Option {
+	return func(m *Mongo) {
+		m.database = database
+	}
+}
+
+func WithCollection(collection string) Option {
+	return func(m *Mongo) {
+		m.collection = collection
+	}
 }
 
 func (m *Mongo) Connect(ctx context.Context) error {
@@ -43,86 +110,516 @@ func (m *Mongo) Connect(ctx context.Context) error {
 	return err
 }
 
+func (m *Mongo) Ping(ctx context.Context) error {
+	if m.client == nil {
+		return errors.New("mongo client is not connected")
+	}
+	return m.client.Ping(ctx, readpref.Primary())
+}
+
 func (m *Mongo) Disconnect(ctx context.Context) error {
 	return m.client.Disconnect(ctx)
 }
 
-func (m *Mongo) Find(ctx context.Context, tags []string, difficulty question.Difficulty) ([]question.Algorithm, error) {
-	filterQuery := bson.M{}
-	if tags != nil {
-		filterQuery["tags"] = bson.M{"$in": tags}
-	}
-
-	if difficulty != "" {
-		filterQuery["difficulty"] = string(difficulty)
+func (m *Mongo) Find(ctx context.Context, f question.Filter) (*question.Page, error) {
+	pipeline, err := findPipeline(f)
+	if err != nil {
+		return nil, err
 	}
 
-	cursor, err := m.lq().Find(ctx, filterQuery)
+	cursor, err := m.lq().Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
 
 	var dbQuestions AlgoQuestions
-	err = cursor.All(ctx, &dbQuestions)
-	return dbQuestions.to(), err
+	if err := cursor.All(ctx, &dbQuestions); err != nil {
+		return nil, err
+	}
+	return question.NewPage(dbQuestions.to(), f), nil
 }
 
 func (m *Mongo) Save(ctx context.Context, q *question.Algorithm) (string, error) {
 	res, err := m.lq().InsertOne(ctx, fromQuestion(q))
 	if err != nil {
-		return "", err
+		return "", translateErr(err)
 	}
 	id, _ := res.InsertedID.(primitive.ObjectID)
 	return id.Hex(), nil
 }
 
 func (m *Mongo) Get(ctx context.Context, id string) (*question.Algorithm, error) {
-	oid, _ := primitive.ObjectIDFromHex(id)
+	oid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
 
 	var aq AlgoQuestion
-	err := m.lq().FindOne(ctx, bson.M{"_id": oid}).Decode(&aq)
+	if err := m.lq().FindOne(ctx, liveFilter(oid)).Decode(&aq); err != nil {
+		return nil, translateErr(err)
+	}
 	question := aq.to()
-	return &question, err
+	return &question, nil
 }
 
-func (m *Mongo) Update(ctx context.Context, id string, q *question.Algorithm) error {
-	oid, _ := primitive.ObjectIDFromHex(id)
+func (m *Mongo) FindBySlug(ctx context.Context, slug string) (*question.Algorithm, error) {
+	var aq AlgoQuestion
+	if err := m.lq().FindOne(ctx, bson.M{"slug": slug, "deletedAt": bson.M{"$exists": false}}).Decode(&aq); err != nil {
+		return nil, translateErr(err)
+	}
+	question := aq.to()
+	return &question, nil
+}
 
-	update := bson.M{"$set": bson.M{
-		"title":      q.Title,
-		"content":    q.Content,
-		"template":   q.Template,
-		"difficulty": string(q.Difficulty),
-		"tags":       q.Tags,
-	}}
-	_, err := m.lq().UpdateByID(ctx, oid, update)
-	return err
+// Update sets only the given fields, the others are left as they are stored.
+func (m *Mongo) Update(ctx context.Context, id string, q *question.Algorithm, fields ...question.Field) error {
+	oid, err := parseID(id)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		fields = question.Fields
+	}
+
+	set, unset := bson.M{}, bson.M{}
+	for _, field := range fields {
+		switch field {
+		case question.FieldSlug:
+			if q.Slug != "" {
+				set["slug"] = q.Slug
+			} else {
+				unset["slug"] = ""
+			}
+		case question.FieldTitle:
+			set["title"] = q.Title
+		case question.FieldContent:
+			set["content"] = q.Content
+		case question.FieldTemplates:
+			set["templates"] = fromTemplates(q.Templates)
+			// template is the single language template questions had before templates per language
+			unset["template"] = ""
+		case question.FieldDifficulty:
+			set["difficulty"] = string(q.Difficulty)
+		case question.FieldTags:
+			set["tags"] = q.Tags
+		case question.FieldTestCases:
+			set["testCases"] = fromTestCases(q.TestCases)
+		case question.FieldEditorial:
+			set["editorial"] = fromEditorial(q.Editorial)
+		}
+	}
+	update := bson.M{"$inc": bson.M{"version": 1}}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	var updated AlgoQuestion
+	err = m.lq().FindOneAndUpdate(ctx, versionFilter(oid, q.Version), update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"version": 1}),
+	).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return m.notMatched(ctx, oid, question.ErrPreconditionFailed)
+	}
+	if err != nil {
+		return translateErr(err)
+	}
+	q.Version = updated.Version
+	return nil
 }
 
-func (m *Mongo) Delete(ctx context.Context, id string) error {
-	oid, err := primitive.ObjectIDFromHex(id)
+func (m *Mongo) SetStatus(ctx context.Context, id string, status question.Status, version int) error {
+	oid, err := parseID(id)
 	if err != nil {
 		return err
 	}
 
-	filter := bson.M{"_id": oid}
-	_, err = m.lq().DeleteOne(ctx, filter)
-	return err
+	update := bson.M{
+		"$set": bson.M{"status": string(status)},
+		"$inc": bson.M{"version": 1},
+	}
+	res, err := m.lq().UpdateOne(ctx, versionFilter(oid, version), update)
+	if err != nil {
+		return translateErr(err)
+	}
+	if res.MatchedCount == 0 {
+		return m.notMatched(ctx, oid, question.ErrPreconditionFailed)
+	}
+	return nil
+}
+
+func (m *Mongo) Delete(ctx context.Context, id string, version int) error {
+	oid, err := parseID(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{"deletedAt": time.Now()}}
+	res, err := m.lq().UpdateOne(ctx, versionFilter(oid, version), update)
+	if err != nil {
+		return translateErr(err)
+	}
+	if res.MatchedCount == 0 {
+		return m.notMatched(ctx, oid, question.ErrPreconditionFailed)
+	}
+	return nil
+}
+
+func (m *Mongo) Trashed(ctx context.Context, id string) (*question.Algorithm, error) {
+	oid, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var aq AlgoQuestion
+	if err := m.lq().FindOne(ctx, bson.M{"_id": oid, "deletedAt": bson.M{"$exists": true}}).Decode(&aq); err != nil {
+		return nil, translateErr(err)
+	}
+	question := aq.to()
+	return &question, nil
+}
+
+func (m *Mongo) Undelete(ctx context.Context, id string) error {
+	oid, err := parseID(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": oid, "deletedAt": bson.M{"$exists": true}}
+	res, err := m.lq().UpdateOne(ctx, filter, bson.M{"$unset": bson.M{"deletedAt": ""}})
+	if err != nil {
+		return translateErr(err)
+	}
+	if res.MatchedCount == 0 {
+		return question.ErrNotFound
+	}
+	return nil
+}
+
+// Purge deletes the questions one by one, so a question undeleted while purging is kept.
+func (m *Mongo) Purge(ctx context.Context, before time.Time) ([]string, error) {
+	filter := bson.M{"deletedAt": bson.M{"$lt": before}}
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := m.lq().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, doc := range docs {
+		filter["_id"] = doc.ID
+		res, err := m.lq().DeleteOne(ctx, filter)
+		if err != nil {
+			return ids, err
+		}
+		if res.DeletedCount > 0 {
+			ids = append(ids, doc.ID.Hex())
+		}
+	}
+	return ids, nil
+}
+
+func (m *Mongo) AddTestCase(ctx context.Context, id string, tc question.TestCase) error {
+	oid, err := parseID(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$push": bson.M{"testCases": fromTestCases([]question.TestCase{tc})[0]},
+		"$inc":  bson.M{"version": 1},
+	}
+	res, err := m.lq().UpdateOne(ctx, liveFilter(oid), update)
+	if err != nil {
+		return translateErr(err)
+	}
+	if res.MatchedCount == 0 {
+		return question.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateTestCase replaces the test case with the id of tc in place, the other cases are not rewritten.
+func (m *Mongo) UpdateTestCase(ctx context.Context, id string, tc question.TestCase) error {
+	oid, err := parseID(id)
+	if err != nil {
+		return err
+	}
+
+	filter := liveFilter(oid)
+	filter["testCases.id"] = tc.ID
+	update := bson.M{
+		"$set": bson.M{"testCases.$": fromTestCases([]question.TestCase{tc})[0]},
+		"$inc": bson.M{"version": 1},
+	}
+	res, err := m.lq().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return translateErr(err)
+	}
+	if res.MatchedCount == 0 {
+		return m.notMatched(ctx, oid, fmt.Errorf("%w: %s", question.ErrTestCaseNotFound, tc.ID))
+	}
+	return nil
+}
+
+func (m *Mongo) DeleteTestCase(ctx context.Context, id, testCaseID string) error {
+	oid, err := parseID(id)
+	if err != nil {
+		return err
+	}
+
+	// a question keeps at least one test case, so only one with a second case matches
+	filter := liveFilter(oid)
+	filter["testCases.id"] = testCaseID
+	filter["testCases.1"] = bson.M{"$exists": true}
+	update := bson.M{
+		"$pull": bson.M{"testCases": bson.M{"id": testCaseID}},
+		"$inc":  bson.M{"version": 1},
+	}
+	res, err := m.lq().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return translateErr(err)
+	}
+	if res.MatchedCount == 0 {
+		delete(filter, "testCases.1")
+		n, err := m.lq().CountDocuments(ctx, filter)
+		if err != nil {
+			return translateErr(err)
+		}
+		if n > 0 {
+			return question.ErrLastTestCase
+		}
+		return m.notMatched(ctx, oid, fmt.Errorf("%w: %s", question.ErrTestCaseNotFound, testCaseID))
+	}
+	return nil
+}
+
+// notMatched tells a missing question apart from a failed condition after a write matched nothing.
+func (m *Mongo) notMatched(ctx context.Context, oid primitive.ObjectID, conditionErr error) error {
+	n, err := m.lq().CountDocuments(ctx, liveFilter(oid))
+	if err != nil {
+		return translateErr(err)
+	}
+	if n == 0 {
+		return question.ErrNotFound
+	}
+	return conditionErr
+}
+
+// liveFilter matches the question unless it is in the trash.
+func liveFilter(oid primitive.ObjectID) bson.M {
+	return bson.M{"_id": oid, "deletedAt": bson.M{"$exists": false}}
+}
+
+// versionFilter matches the question only at the given version, any version for question.AnyVersion.
+func versionFilter(oid primitive.ObjectID, version int) bson.M {
+	filter := liveFilter(oid)
+	if version != question.AnyVersion {
+		filter["version"] = version
+	}
+	return filter
 }
 
 func (m *Mongo) lq() *mongo.Collection {
-	return m.client.Database(_databaseListing).Collection(_collectionQuestion)
+	return m.client.Database(m.database).Collection(m.collection)
+}
+
+func parseID(id string) (primitive.ObjectID, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: %q", question.ErrInvalidID, id)
+	}
+	return oid, nil
+}
+
+func translateErr(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return question.ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return question.ErrConflict
+	default:
+		return err
+	}
+}
+
+func findPipeline(f question.Filter) (mongo.Pipeline, error) {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filterQuery(f)}}}
+
+	if f.Query != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}})
+	}
+
+	sortField, sortOrder := "_id", 1
+	switch f.Sort {
+	case question.SortTitle:
+		sortField = "title"
+	case question.SortDifficulty:
+		sortField = "difficultyLevel"
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"difficultyLevel": difficultyLevelExpr()}}})
+	case question.SortRelevance:
+		sortField, sortOrder = "score", -1
+	}
+
+	cursorQuery, err := cursorQuery(f, sortField, sortOrder)
+	if err != nil {
+		return nil, err
+	}
+	if cursorQuery != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: cursorQuery}})
+	}
+
+	sort := bson.D{{Key: "_id", Value: 1}}
+	if sortField != "_id" {
+		sort = append(bson.D{{Key: sortField, Value: sortOrder}}, sort...)
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sort}})
+
+	// one more than asked for, to know whether there is a next page
+	if f.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: f.Limit + 1}})
+	}
+	return pipeline, nil
+}
+
+func filterQuery(f question.Filter) bson.M {
+	filterQuery := bson.M{"deletedAt": bson.M{"$exists": f.Deleted}}
+	if f.Query != "" {
+		filterQuery["$text"] = bson.M{"$search": f.Query}
+	}
+
+	tagsQuery := bson.M{}
+	excluded := f.ExcludeTags
+	if len(f.Tags) > 0 {
+		switch f.TagMatch {
+		case question.TagMatchAll:
+			tagsQuery["$all"] = f.Tags
+		case question.TagMatchNone:
+			excluded = append(append([]string{}, excluded...), f.Tags...)
+		default:
+			tagsQuery["$in"] = f.Tags
+		}
+	}
+	if len(excluded) > 0 {
+		tagsQuery["$nin"] = excluded
+	}
+	if len(tagsQuery) > 0 {
+		filterQuery["tags"] = tagsQuery
+	}
+
+	if len(f.Difficulties) > 0 {
+		difficulties := make([]string, 0, len(f.Difficulties))
+		for _, d := range f.Difficulties {
+			difficulties = append(difficulties, string(d))
+		}
+		filterQuery["difficulty"] = bson.M{"$in": difficulties}
+	}
+
+	if len(f.Statuses) > 0 {
+		statuses := bson.A{}
+		for _, s := range f.Statuses {
+			statuses = append(statuses, string(s))
+			// $in matches missing fields by null
+			if s == question.StatusPublished {
+				statuses = append(statuses, nil)
+			}
+		}
+		filterQuery["status"] = bson.M{"$in": statuses}
+	}
+
+	return filterQuery
+}
+
+func cursorQuery(f question.Filter, sortField string, sortOrder int) (bson.M, error) {
+	c, err := f.DecodeCursor()
+	if err != nil || c == nil {
+		return nil, err
+	}
+
+	oid, err := primitive.ObjectIDFromHex(c.ID)
+	if err != nil {
+		return nil, question.ErrInvalidCursor
+	}
+	if sortField == "_id" {
+		return bson.M{"_id": bson.M{"$gt": oid}}, nil
+	}
+
+	var value interface{} = c.Value
+	switch f.Sort {
+	case question.SortDifficulty:
+		value, err = strconv.Atoi(c.Value)
+	case question.SortRelevance:
+		value, err = strconv.ParseFloat(c.Value, 64)
+	}
+	if err != nil {
+		return nil, question.ErrInvalidCursor
+	}
+
+	after := "$gt"
+	if sortOrder < 0 {
+		after = "$lt"
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{sortField: bson.M{after: value}},
+		bson.M{sortField: value, "_id": bson.M{"$gt": oid}},
+	}}, nil
+}
+
+func difficultyLevelExpr() bson.M {
+	var branches bson.A
+	for _, d := range []question.Difficulty{question.Easy, question.Medium, question.Hard} {
+		branches = append(branches, bson.M{
+			"case": bson.M{"$eq": bson.A{"$difficulty", string(d)}},
+			"then": d.Level(),
+		})
+	}
+	return bson.M{"$switch": bson.M{"branches": branches, "default": 0}}
 }
 
 func (a *AlgoQuestion) to() question.Algorithm {
-	return question.Algorithm{
+	q := question.Algorithm{
 		ID:         a.ID.Hex(),
+		Slug:       a.Slug,
 		Title:      a.Title,
 		Content:    a.Content,
-		Template:   a.Template,
+		Templates:  toTemplates(a.Templates),
 		Difficulty: question.Difficulty(a.Difficulty),
+		Status:     question.Status(a.Status),
 		Tags:       a.Tags,
+		TestCases:  TestCases(a.TestCases).to(),
+		Editorial:  a.Editorial.to(),
+		CreatedAt:  a.ID.Timestamp(),
+		Version:    a.Version,
+		Score:      a.Score,
+	}
+	if q.Status == "" {
+		q.Status = question.StatusPublished
+	}
+	if q.Version == 0 {
+		q.Version = 1
+	}
+	if _, ok := q.Templates[_legacyTemplateLanguage]; a.Template != "" && !ok {
+		if q.Templates == nil {
+			q.Templates = make(map[question.Language]string, 1)
+		}
+		q.Templates[_legacyTemplateLanguage] = a.Template
 	}
+	if a.DeletedAt != nil {
+		q.DeletedAt = a.DeletedAt.UTC()
+	}
+	return q
 }
 
 func (algoQuestions AlgoQuestions) to() (questions []question.Algorithm) {
@@ -135,10 +632,76 @@ func (algoQuestions AlgoQuestions) to() (questions []question.Algorithm) {
 func fromQuestion(q *question.Algorithm) *AlgoQuestion {
 	return &AlgoQuestion{
 		ID:         primitive.NewObjectID(),
+		Slug:       q.Slug,
 		Title:      q.Title,
 		Content:    q.Content,
-		Template:   q.Template,
+		Templates:  fromTemplates(q.Templates),
 		Difficulty: string(q.Difficulty),
+		Status:     string(q.Status),
 		Tags:       q.Tags,
+		TestCases:  fromTestCases(q.TestCases),
+		Editorial:  fromEditorial(q.Editorial),
+		Version:    1,
+	}
+}
+
+func (t TestCase) to() question.TestCase {
+	return question.TestCase{
+		ID:          t.ID,
+		Input:       t.Input,
+		Output:      t.Output,
+		Hidden:      t.Hidden,
+		Explanation: t.Explanation,
+		Weight:      t.Weight,
+	}
+}
+
+func (testCases TestCases) to() (tcs []question.TestCase) {
+	for _, tc := range testCases {
+		tcs = append(tcs, tc.to())
+	}
+	return tcs
+}
+
+func (e Editorial) to() question.Editorial {
+	return question.Editorial{Explanation: e.Explanation}
+}
+
+func fromTestCases(tcs []question.TestCase) []TestCase {
+	testCases := make([]TestCase, 0, len(tcs))
+	for _, tc := range tcs {
+		testCases = append(testCases, TestCase{
+			ID:          tc.ID,
+			Input:       tc.Input,
+			Output:      tc.Output,
+			Hidden:      tc.Hidden,
+			Explanation: tc.Explanation,
+			Weight:      tc.Weight,
+		})
 	}
+	return testCases
+}
+
+func toTemplates(templates map[string]string) map[question.Language]string {
+	if len(templates) == 0 {
+		return nil
+	}
+
+	res := make(map[question.Language]string, len(templates))
+	for lang, code := range templates {
+		res[question.Language(lang)] = code
+	}
+	return res
+}
+
+func fromTemplates(templates map[question.Language]string) map[string]string {
+	res := make(map[string]string, len(templates))
+	for lang, code := range templates {
+		res[string(lang)] = code
+	}
+	return res
+}
+
+func fromEditorial(e question.Editorial) Editorial {
+	return Editorial{Explanation: e.Explanation}
 }
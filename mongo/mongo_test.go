@@ -4,9 +4,11 @@ import (
 	"context"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/codigician/question"
 	qmongo "github.com/codigician/question/mongo"
+	"github.com/codigician/question/repositorytest"
 	"github.com/stretchr/testify/suite"
 	"github.com/testcontainers/testcontainers-go"
 	"go.mongodb.org/mongo-driver/bson"
@@ -47,6 +49,17 @@ func (s *QuestionMongoTestSuite) SetupSuite() {
 	if err := s.mongo.Connect(ctx); err != nil {
 		log.Fatalf("mongo connect: %v\n", err)
 	}
+	if err := s.mongo.EnsureIndexes(ctx); err != nil {
+		log.Fatalf("ensure indexes: %v\n", err)
+	}
+}
+
+func (s *QuestionMongoTestSuite) SetupTest() {
+	for _, collection := range []string{_collection, _collection + "_revisions"} {
+		if _, err := s.client.Database(_database).Collection(collection).DeleteMany(context.Background(), bson.M{}); err != nil {
+			log.Fatalf("delete many: %v\n", err)
+		}
+	}
 }
 
 func (s *QuestionMongoTestSuite) TearDownSuite() {
@@ -61,6 +74,20 @@ func (s *QuestionMongoTestSuite) TearDownSuite() {
 	}
 }
 
+func (s *QuestionMongoTestSuite) TestRepositoryConformance() {
+	repositorytest.Run(s.T(), func(t *testing.T) question.Repository {
+		s.SetupTest()
+		return s.mongo
+	})
+}
+
+func (s *QuestionMongoTestSuite) TestRevisionRepositoryConformance() {
+	repositorytest.RunRevisions(s.T(), func(t *testing.T) question.RevisionRepository {
+		s.SetupTest()
+		return s.mongo
+	})
+}
+
 func (s *QuestionMongoTestSuite) TestFind() {
 	ctx := context.Background()
 
@@ -70,11 +97,199 @@ func (s *QuestionMongoTestSuite) TestFind() {
 		s.createMongoQuestion(question.Medium, []string{"tree", "binary tree"}),
 	)
 
-	questions, err := s.mongo.Find(ctx, []string{"tree", "binary tree"}, question.Easy)
-	log.Println(questions)
+	page, err := s.mongo.Find(ctx, question.Filter{
+		Tags:         []string{"tree", "binary tree"},
+		Difficulties: []question.Difficulty{question.Easy},
+	})
+	log.Println(page)
+
+	s.Nil(err)
+	s.Len(page.Items, 2)
+	s.Empty(page.NextCursor)
+	for _, q := range page.Items {
+		s.Len(q.TestCases, 2)
+		s.Equal("Explanation", q.Editorial.Explanation)
+	}
+}
+
+func (s *QuestionMongoTestSuite) TestFind_TagMatchModes() {
+	ctx := context.Background()
+
+	treeBFS := s.createMongoQuestion(question.Easy, []string{"tree", "bfs"})
+	tree := s.createMongoQuestion(question.Medium, []string{"tree"})
+	graphBFS := s.createMongoQuestion(question.Hard, []string{"graph", "bfs"})
+	s.insertQuestions(ctx, treeBFS, tree, graphBFS)
+
+	testCases := []struct {
+		scenario    string
+		filter      question.Filter
+		expectedIDs []primitive.ObjectID
+	}{
+		{
+			scenario:    "any",
+			filter:      question.Filter{Tags: []string{"tree", "bfs"}, TagMatch: question.TagMatchAny},
+			expectedIDs: []primitive.ObjectID{treeBFS.ID, tree.ID, graphBFS.ID},
+		},
+		{
+			scenario:    "all",
+			filter:      question.Filter{Tags: []string{"tree", "bfs"}, TagMatch: question.TagMatchAll},
+			expectedIDs: []primitive.ObjectID{treeBFS.ID},
+		},
+		{
+			scenario:    "none",
+			filter:      question.Filter{Tags: []string{"graph"}, TagMatch: question.TagMatchNone},
+			expectedIDs: []primitive.ObjectID{treeBFS.ID, tree.ID},
+		},
+		{
+			scenario:    "any with excluded tags",
+			filter:      question.Filter{Tags: []string{"tree"}, ExcludeTags: []string{"bfs"}},
+			expectedIDs: []primitive.ObjectID{tree.ID},
+		},
+		{
+			scenario:    "multiple difficulties",
+			filter:      question.Filter{Difficulties: []question.Difficulty{question.Easy, question.Hard}},
+			expectedIDs: []primitive.ObjectID{treeBFS.ID, graphBFS.ID},
+		},
+	}
+	for _, tC := range testCases {
+		page, err := s.mongo.Find(ctx, tC.filter)
+		s.Nil(err)
+
+		var actualIDs []primitive.ObjectID
+		for _, q := range page.Items {
+			oid, _ := primitive.ObjectIDFromHex(q.ID)
+			actualIDs = append(actualIDs, oid)
+		}
+		s.Equal(tC.expectedIDs, actualIDs, tC.scenario)
+	}
+}
+
+func (s *QuestionMongoTestSuite) TestFind_Search() {
+	ctx := context.Background()
+
+	inTitle := s.createMongoQuestion(question.Easy, []string{"array"})
+	inTitle.Title = "Binary Search"
+	inContent := s.createMongoQuestion(question.Medium, []string{"array"})
+	inContent.Content = "Use binary search to find the peak"
+	inContentHard := s.createMongoQuestion(question.Hard, []string{"array"})
+	inContentHard.Content = "Binary search the answer"
+	unrelated := s.createMongoQuestion(question.Easy, []string{"tree"})
+	s.insertQuestions(ctx, inContent, inTitle, inContentHard, unrelated)
+
+	page, err := s.mongo.Find(ctx, question.Filter{Query: "binary search", Sort: question.SortRelevance})
+	s.Nil(err)
+	s.Len(page.Items, 3)
+	s.Equal(inTitle.ID.Hex(), page.Items[0].ID)
+	s.GreaterOrEqual(page.Items[0].Score, page.Items[1].Score)
+
+	page, err = s.mongo.Find(ctx, question.Filter{
+		Query:        "binary search",
+		Difficulties: []question.Difficulty{question.Medium, question.Hard},
+		Sort:         question.SortRelevance,
+		Limit:        1,
+	})
+	s.Nil(err)
+	s.Len(page.Items, 1)
+	s.NotEmpty(page.NextCursor)
+
+	next, err := s.mongo.Find(ctx, question.Filter{
+		Query:        "binary search",
+		Difficulties: []question.Difficulty{question.Medium, question.Hard},
+		Sort:         question.SortRelevance,
+		Limit:        1,
+		Cursor:       page.NextCursor,
+	})
+	s.Nil(err)
+	s.Len(next.Items, 1)
+	s.Empty(next.NextCursor)
+	s.NotEqual(page.Items[0].ID, next.Items[0].ID)
+}
+
+func (s *QuestionMongoTestSuite) TestFind_Paginate() {
+	ctx := context.Background()
+
+	hard := s.createMongoQuestion(question.Hard, []string{"tree"})
+	hard.Title = "A"
+	easy := s.createMongoQuestion(question.Easy, []string{"tree"})
+	easy.Title = "C"
+	medium := s.createMongoQuestion(question.Medium, []string{"tree"})
+	medium.Title = "B"
+	s.insertQuestions(ctx, hard, easy, medium)
+
+	testCases := []struct {
+		sort        question.SortKey
+		expectedIDs []primitive.ObjectID
+	}{
+		{question.SortCreated, []primitive.ObjectID{hard.ID, easy.ID, medium.ID}},
+		{question.SortTitle, []primitive.ObjectID{hard.ID, medium.ID, easy.ID}},
+		{question.SortDifficulty, []primitive.ObjectID{easy.ID, medium.ID, hard.ID}},
+	}
+	for _, tC := range testCases {
+		f := question.Filter{Limit: 2, Sort: tC.sort}
+
+		first, err := s.mongo.Find(ctx, f)
+		s.Nil(err)
+		s.Len(first.Items, 2)
+		s.NotEmpty(first.NextCursor)
+
+		f.Cursor = first.NextCursor
+		second, err := s.mongo.Find(ctx, f)
+		s.Nil(err)
+		s.Len(second.Items, 1)
+		s.Empty(second.NextCursor)
+
+		var actualIDs []primitive.ObjectID
+		for _, q := range append(first.Items, second.Items...) {
+			oid, _ := primitive.ObjectIDFromHex(q.ID)
+			actualIDs = append(actualIDs, oid)
+		}
+		s.Equal(tC.expectedIDs, actualIDs, "sort %s", tC.sort)
+	}
+}
 
+func (s *QuestionMongoTestSuite) TestEnsureIndexes_Idempotent() {
+	ctx := context.Background()
+
+	s.Nil(s.mongo.EnsureIndexes(ctx))
+	s.Nil(s.mongo.EnsureIndexes(ctx))
+
+	names, err := s.mongo.ListIndexes(ctx)
 	s.Nil(err)
-	s.Len(questions, 2)
+	s.ElementsMatch([]string{"_id_", "tags", "difficulty", "tags_difficulty", "text_search", "slug_unique", "status", "deleted_at"}, names)
+}
+
+func (s *QuestionMongoTestSuite) TestDropStaleIndexes() {
+	ctx := context.Background()
+
+	_, err := s.client.Database(_database).Collection(_collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "template", Value: 1}},
+		Options: options.Index().SetName("stale_template"),
+	})
+	s.Nil(err)
+
+	dropped, err := s.mongo.DropStaleIndexes(ctx)
+	s.Nil(err)
+	s.Equal([]string{"stale_template"}, dropped)
+
+	names, err := s.mongo.ListIndexes(ctx)
+	s.Nil(err)
+	s.NotContains(names, "stale_template")
+	s.Contains(names, "slug_unique")
+}
+
+func (s *QuestionMongoTestSuite) TestSave_DuplicateSlug_ReturnErrConflict() {
+	ctx := context.Background()
+
+	first := s.createQuestion(question.Easy, []string{"array"})
+	first.Slug = "two-sum"
+	second := s.createQuestion(question.Easy, []string{"array"})
+	second.Slug = "two-sum"
+
+	_, err := s.mongo.Save(ctx, &first)
+	s.Nil(err)
+
+	_, err = s.mongo.Save(ctx, &second)
+	s.ErrorIs(err, question.ErrConflict)
 }
 
 func (s *QuestionMongoTestSuite) TestSave() {
@@ -90,8 +305,27 @@ func (s *QuestionMongoTestSuite) TestSave() {
 	s.Equal(expectedQuestion.Content, actualQuestion.Content)
 	s.Equal(expectedQuestion.Tags, actualQuestion.Tags)
 	s.Equal(string(expectedQuestion.Difficulty), actualQuestion.Difficulty)
-	s.Equal(expectedQuestion.Template, actualQuestion.Template)
+	s.Equal(map[string]string{"go": "package main", "python": "def solve():"}, actualQuestion.Templates)
 	s.Equal(expectedQuestion.Title, actualQuestion.Title)
+	s.Equal(expectedQuestion.Editorial.Explanation, actualQuestion.Editorial.Explanation)
+	s.Equal([]qmongo.TestCase{
+		{ID: "tc-1", Input: "1 2", Output: "3"},
+		{ID: "tc-2", Input: "2 2", Output: "4", Hidden: true, Explanation: "two twos", Weight: 2},
+	}, actualQuestion.TestCases)
+}
+
+func (s *QuestionMongoTestSuite) TestSaveAndGet_RoundTripTestCasesAndEditorial() {
+	ctx := context.Background()
+
+	expectedQuestion := s.createQuestion(question.Medium, []string{"math"})
+
+	id, err := s.mongo.Save(ctx, &expectedQuestion)
+	s.Nil(err)
+
+	actualQuestion, err := s.mongo.Get(ctx, id)
+	s.Nil(err)
+	s.Equal(expectedQuestion.TestCases, actualQuestion.TestCases)
+	s.Equal(expectedQuestion.Editorial, actualQuestion.Editorial)
 }
 
 func (s *QuestionMongoTestSuite) TestGet() {
@@ -104,25 +338,143 @@ func (s *QuestionMongoTestSuite) TestGet() {
 	s.Nil(err)
 	s.Equal([]string{"binary tree", "tree", "data structures"}, question.Tags)
 	s.Equal("easy", string(question.Difficulty))
+	s.Len(question.TestCases, 2)
+	s.Equal("1 2", question.TestCases[0].Input)
+	s.Equal("3", question.TestCases[0].Output)
+	s.Equal("Explanation", question.Editorial.Explanation)
+}
+
+func (s *QuestionMongoTestSuite) TestGet_NotExistingID_ReturnErrNotFound() {
+	_, err := s.mongo.Get(context.Background(), primitive.NewObjectID().Hex())
+
+	s.ErrorIs(err, question.ErrNotFound)
+}
+
+func (s *QuestionMongoTestSuite) TestGetUpdateDelete_MalformedID_ReturnErrInvalidID() {
+	ctx := context.Background()
+	q := s.createQuestion(question.Easy, []string{"tree"})
+
+	_, err := s.mongo.Get(ctx, "not-an-object-id")
+	s.ErrorIs(err, question.ErrInvalidID)
+
+	s.ErrorIs(s.mongo.Update(ctx, "not-an-object-id", &q), question.ErrInvalidID)
+	s.ErrorIs(s.mongo.Delete(ctx, "not-an-object-id", question.AnyVersion), question.ErrInvalidID)
+}
+
+func (s *QuestionMongoTestSuite) TestUpdateDelete_NotExistingID_ReturnErrNotFound() {
+	ctx := context.Background()
+	q := s.createQuestion(question.Easy, []string{"tree"})
+
+	s.ErrorIs(s.mongo.Update(ctx, primitive.NewObjectID().Hex(), &q), question.ErrNotFound)
+	s.ErrorIs(s.mongo.Delete(ctx, primitive.NewObjectID().Hex(), question.AnyVersion), question.ErrNotFound)
 }
 
 func (s *QuestionMongoTestSuite) TestDelete() {
 	ctx := context.Background()
 
-	question := s.createMongoQuestion(question.Hard, []string{"data structures"})
-	s.insertQuestions(ctx, question)
+	mq := s.createMongoQuestion(question.Hard, []string{"data structures"})
+	s.insertQuestions(ctx, mq)
 	// get question will fail if not inserted
-	insertedQuestion := s.getQuestion(ctx, question.ID.Hex())
+	insertedQuestion := s.getQuestion(ctx, mq.ID.Hex())
 	log.Println(insertedQuestion)
 
-	if err := s.mongo.Delete(ctx, question.ID.Hex()); err != nil {
+	if err := s.mongo.Delete(ctx, mq.ID.Hex(), question.AnyVersion); err != nil {
 		log.Fatalf("delete one: %v\n", err)
 	}
 
-	res := s.client.Database(_database).Collection(_collection).FindOne(ctx, bson.M{"_id": question.ID})
+	// the document stays in the trash until it is purged
+	var trashed bson.M
+	s.Nil(s.client.Database(_database).Collection(_collection).FindOne(ctx, bson.M{"_id": mq.ID}).Decode(&trashed))
+	s.Contains(trashed, "deletedAt")
+
+	purged, err := s.mongo.Purge(ctx, time.Now().Add(time.Minute))
+	s.Nil(err)
+	s.Equal([]string{mq.ID.Hex()}, purged)
+
+	res := s.client.Database(_database).Collection(_collection).FindOne(ctx, bson.M{"_id": mq.ID})
 	s.Equal(res.Err(), mongo.ErrNoDocuments)
 }
 
+func (s *QuestionMongoTestSuite) TestLegacyQuestionWithoutStatus_CountsAsPublished() {
+	ctx := context.Background()
+
+	mq := s.createMongoQuestion(question.Easy, []string{"array"})
+	s.insertQuestions(ctx, mq)
+
+	q, err := s.mongo.Get(ctx, mq.ID.Hex())
+	s.Nil(err)
+	s.Equal(question.StatusPublished, q.Status)
+
+	page, err := s.mongo.Find(ctx, question.Filter{Statuses: []question.Status{question.StatusPublished}})
+	s.Nil(err)
+	s.Len(page.Items, 1)
+
+	page, err = s.mongo.Find(ctx, question.Filter{Statuses: []question.Status{question.StatusDraft}})
+	s.Nil(err)
+	s.Empty(page.Items)
+}
+
+func (s *QuestionMongoTestSuite) TestLegacyTemplate_ReadAndMigratedAsGoTemplate() {
+	ctx := context.Background()
+
+	legacy := s.createMongoQuestion(question.Easy, []string{"array"})
+	legacy.Templates, legacy.Template = nil, "func twoSum() {}"
+	both := s.createMongoQuestion(question.Easy, []string{"array"})
+	both.Templates, both.Template = map[string]string{"go": "func threeSum() {}", "js": "function threeSum() {}"}, "func old() {}"
+	s.insertQuestions(ctx, legacy, both)
+
+	q, err := s.mongo.Get(ctx, legacy.ID.Hex())
+	s.Nil(err)
+	s.Equal(map[question.Language]string{question.Go: "func twoSum() {}"}, q.Templates)
+
+	s.Nil(s.mongo.Migrate(ctx))
+	s.Nil(s.mongo.Migrate(ctx))
+
+	migrated := s.getQuestion(ctx, legacy.ID.Hex())
+	s.Empty(migrated.Template)
+	s.Equal(map[string]string{"go": "func twoSum() {}"}, migrated.Templates)
+	migrated = s.getQuestion(ctx, both.ID.Hex())
+	s.Empty(migrated.Template)
+	s.Equal(both.Templates, migrated.Templates)
+}
+
+func (s *QuestionMongoTestSuite) TestLegacyTestCasesWithoutID_MigratedWithIDs() {
+	ctx := context.Background()
+
+	legacy := s.createMongoQuestion(question.Easy, []string{"array"})
+	legacy.TestCases[1].ID = ""
+	s.insertQuestions(ctx, legacy)
+
+	s.Nil(s.mongo.Migrate(ctx))
+	migrated := s.getQuestion(ctx, legacy.ID.Hex())
+	s.Equal("tc-1", migrated.TestCases[0].ID)
+	s.NotEmpty(migrated.TestCases[1].ID)
+
+	s.Nil(s.mongo.Migrate(ctx))
+	s.Equal(migrated.TestCases, s.getQuestion(ctx, legacy.ID.Hex()).TestCases)
+}
+
+func (s *QuestionMongoTestSuite) TestLegacyQuestionWithoutVersion_ReadAndMigratedAsVersion1() {
+	ctx := context.Background()
+
+	legacy := s.createMongoQuestion(question.Easy, []string{"array"})
+	s.insertQuestions(ctx, legacy)
+	_, err := s.client.Database(_database).Collection(_collection).
+		UpdateOne(ctx, bson.M{"_id": legacy.ID}, bson.M{"$unset": bson.M{"version": ""}})
+	s.Nil(err)
+
+	q, err := s.mongo.Get(ctx, legacy.ID.Hex())
+	s.Nil(err)
+	s.Equal(1, q.Version)
+
+	s.Nil(s.mongo.Migrate(ctx))
+	s.Equal(1, s.getQuestion(ctx, legacy.ID.Hex()).Version)
+
+	s.Nil(s.mongo.Update(ctx, legacy.ID.Hex(), q))
+	s.Equal(2, q.Version)
+	s.ErrorIs(s.mongo.Delete(ctx, legacy.ID.Hex(), 1), question.ErrPreconditionFailed)
+}
+
 func (s *QuestionMongoTestSuite) TestUpdate() {
 	ctx := context.Background()
 
@@ -132,9 +484,12 @@ func (s *QuestionMongoTestSuite) TestUpdate() {
 	q := question.Algorithm{
 		Title:      "Updated Title",
 		Content:    "Updated Content",
-		Template:   "Updated Template",
+		Templates:  map[question.Language]string{question.Java: "class Main {}"},
 		Difficulty: question.Easy,
 		Tags:       []string{"tree"},
+		TestCases:  []question.TestCase{{Input: "updated input", Output: "updated output"}},
+		Editorial:  question.Editorial{Explanation: "Updated Explanation"},
+		Version:    question.AnyVersion,
 	}
 	if err := s.mongo.Update(ctx, mq.ID.Hex(), &q); err != nil {
 		log.Fatalf("update one: %v\n", err)
@@ -145,7 +500,54 @@ func (s *QuestionMongoTestSuite) TestUpdate() {
 	s.Equal(string(q.Difficulty), updatedQuestion.Difficulty)
 	s.Equal(q.Title, updatedQuestion.Title)
 	s.Equal(q.Content, updatedQuestion.Content)
-	s.Equal(q.Template, updatedQuestion.Template)
+	s.Equal(map[string]string{"java": "class Main {}"}, updatedQuestion.Templates)
+	s.Equal([]qmongo.TestCase{{Input: "updated input", Output: "updated output"}}, updatedQuestion.TestCases)
+	s.Equal(q.Editorial.Explanation, updatedQuestion.Editorial.Explanation)
+}
+
+func (s *QuestionMongoTestSuite) TestTestCaseOperations_ChangeOnlyTheAddressedCase() {
+	ctx := context.Background()
+
+	mq := s.createMongoQuestion(question.Hard, []string{"data structures"})
+	s.insertQuestions(ctx, mq)
+	id := mq.ID.Hex()
+
+	s.Nil(s.mongo.AddTestCase(ctx, id, question.TestCase{ID: "tc-3", Input: "3 3", Output: "6"}))
+	s.Nil(s.mongo.UpdateTestCase(ctx, id, question.TestCase{ID: "tc-1", Input: "1 1", Output: "2", Weight: 5}))
+	s.Nil(s.mongo.DeleteTestCase(ctx, id, "tc-2"))
+
+	s.Equal([]qmongo.TestCase{
+		{ID: "tc-1", Input: "1 1", Output: "2", Weight: 5},
+		{ID: "tc-3", Input: "3 3", Output: "6"},
+	}, s.getQuestion(ctx, id).TestCases)
+}
+
+func (s *QuestionMongoTestSuite) TestTestCaseOperations_Missing_ReturnNotFound() {
+	ctx := context.Background()
+
+	mq := s.createMongoQuestion(question.Hard, []string{"data structures"})
+	s.insertQuestions(ctx, mq)
+	missing := primitive.NewObjectID().Hex()
+
+	s.ErrorIs(s.mongo.AddTestCase(ctx, missing, question.TestCase{ID: "tc-3"}), question.ErrNotFound)
+	s.ErrorIs(s.mongo.UpdateTestCase(ctx, missing, question.TestCase{ID: "tc-1"}), question.ErrNotFound)
+	s.ErrorIs(s.mongo.UpdateTestCase(ctx, mq.ID.Hex(), question.TestCase{ID: "tc-9"}), question.ErrTestCaseNotFound)
+	s.ErrorIs(s.mongo.DeleteTestCase(ctx, mq.ID.Hex(), "tc-9"), question.ErrTestCaseNotFound)
+}
+
+func (s *QuestionMongoTestSuite) TestFindBySlug() {
+	ctx := context.Background()
+
+	mq := s.createMongoQuestion(question.Hard, []string{"data structures"})
+	mq.Slug = "two-sum"
+	s.insertQuestions(ctx, mq)
+
+	q, err := s.mongo.FindBySlug(ctx, "two-sum")
+	s.Nil(err)
+	s.Equal(mq.ID.Hex(), q.ID)
+
+	_, err = s.mongo.FindBySlug(ctx, "three-sum")
+	s.ErrorIs(err, question.ErrNotFound)
 }
 
 func (s *QuestionMongoTestSuite) createMongoQuestion(diff question.Difficulty, tags []string) qmongo.AlgoQuestion {
@@ -153,9 +555,14 @@ func (s *QuestionMongoTestSuite) createMongoQuestion(diff question.Difficulty, t
 		ID:         primitive.NewObjectID(),
 		Title:      "Title",
 		Content:    "Content",
-		Template:   "Template",
+		Templates:  map[string]string{"go": "package main", "python": "def solve():"},
 		Difficulty: string(diff),
 		Tags:       tags,
+		TestCases: []qmongo.TestCase{
+			{ID: "tc-1", Input: "1 2", Output: "3"},
+			{ID: "tc-2", Input: "2 2", Output: "4", Hidden: true, Explanation: "two twos", Weight: 2},
+		},
+		Editorial: qmongo.Editorial{Explanation: "Explanation"},
 	}
 }
 
@@ -163,9 +570,14 @@ func (s *QuestionMongoTestSuite) createQuestion(diff question.Difficulty, tags [
 	return question.Algorithm{
 		Title:      "Title",
 		Content:    "Content",
-		Template:   "Template",
+		Templates:  map[question.Language]string{question.Go: "package main", question.Python: "def solve():"},
 		Difficulty: diff,
 		Tags:       tags,
+		TestCases: []question.TestCase{
+			{ID: "tc-1", Input: "1 2", Output: "3"},
+			{ID: "tc-2", Input: "2 2", Output: "4", Hidden: true, Explanation: "two twos", Weight: 2},
+		},
+		Editorial: question.Editorial{Explanation: "Explanation"},
 	}
 }
 
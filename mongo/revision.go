@@ -0,0 +1,157 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/codigician/question"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// _revisionSuffix names the revision collection after the question collection.
+const _revisionSuffix = "_revisions"
+
+type (
+	Revision struct {
+		ID         primitive.ObjectID `bson:"_id"`
+		QuestionID primitive.ObjectID `bson:"questionId"`
+		Number     int                `bson:"number"`
+		Author     string             `bson:"author"`
+		Fields     []string           `bson:"fields"`
+		// Diff is a json merge patch, kept as a string so it reads like the api returns it.
+		Diff     string       `bson:"diff,omitempty"`
+		Question AlgoQuestion `bson:"question"`
+	}
+
+	Revisions []Revision
+)
+
+func (m *Mongo) SaveRevision(ctx context.Context, rev *question.Revision) error {
+	doc, err := fromRevision(rev)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.lr().InsertOne(ctx, doc)
+	return translateErr(err)
+}
+
+func (m *Mongo) FindRevisions(ctx context.Context, questionID string) ([]question.Revision, error) {
+	oid, err := parseID(questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "number", Value: -1}}).
+		SetProjection(bson.M{"diff": 0, "question": 0})
+	cursor, err := m.lr().Find(ctx, bson.M{"questionId": oid}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions Revisions
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions.to(), nil
+}
+
+func (m *Mongo) GetRevision(ctx context.Context, questionID string, number int) (*question.Revision, error) {
+	return m.findRevision(ctx, questionID, bson.M{"number": number}, nil)
+}
+
+func (m *Mongo) LatestRevision(ctx context.Context, questionID string) (*question.Revision, error) {
+	return m.findRevision(ctx, questionID, bson.M{}, bson.D{{Key: "number", Value: -1}})
+}
+
+func (m *Mongo) DeleteRevisions(ctx context.Context, questionID string) error {
+	oid, err := parseID(questionID)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.lr().DeleteMany(ctx, bson.M{"questionId": oid})
+	return err
+}
+
+func (m *Mongo) findRevision(ctx context.Context, questionID string, filter bson.M, sort bson.D) (*question.Revision, error) {
+	oid, err := parseID(questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter["questionId"] = oid
+	opts := options.FindOne()
+	if sort != nil {
+		opts.SetSort(sort)
+	}
+	var r Revision
+	err = m.lr().FindOne(ctx, filter, opts).Decode(&r)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, question.ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	rev := r.to()
+	return &rev, nil
+}
+
+func (m *Mongo) lr() *mongo.Collection {
+	return m.client.Database(m.database).Collection(m.collection + _revisionSuffix)
+}
+
+func (r *Revision) to() question.Revision {
+	rev := question.Revision{
+		QuestionID: r.QuestionID.Hex(),
+		Number:     r.Number,
+		Author:     r.Author,
+		CreatedAt:  r.ID.Timestamp(),
+		Diff:       []byte(r.Diff),
+	}
+	for _, field := range r.Fields {
+		rev.Fields = append(rev.Fields, question.Field(field))
+	}
+	if !r.Question.ID.IsZero() {
+		rev.Question = r.Question.to()
+	}
+	if len(rev.Diff) == 0 {
+		rev.Diff = nil
+	}
+	return rev
+}
+
+func (revisions Revisions) to() []question.Revision {
+	res := make([]question.Revision, 0, len(revisions))
+	for idx := range revisions {
+		res = append(res, revisions[idx].to())
+	}
+	return res
+}
+
+func fromRevision(rev *question.Revision) (*Revision, error) {
+	oid, err := parseID(rev.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := fromQuestion(&rev.Question)
+	q.ID, q.Version = oid, rev.Question.Version
+	doc := &Revision{
+		ID:         primitive.NewObjectID(),
+		QuestionID: oid,
+		Number:     rev.Number,
+		Author:     rev.Author,
+		Fields:     make([]string, 0, len(rev.Fields)),
+		Diff:       string(rev.Diff),
+		Question:   *q,
+	}
+	for _, field := range rev.Fields {
+		doc.Fields = append(doc.Fields, string(field))
+	}
+	return doc, nil
+}
@@ -0,0 +1,206 @@
+package question
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+)
+
+type (
+	PatchType string
+
+	// Field is a part of a question that an update writes on its own.
+	Field string
+
+	// Patch changes a question partially, either as a json merge patch (RFC 7396) or as a
+	// json patch (RFC 6902). Both address the question in the shape of a bundle document
+	// with every field present, test cases are patched by index.
+	Patch struct {
+		Type PatchType
+		Body []byte
+		// Version the patch was written against, AnyVersion patches whatever is stored.
+		Version int
+	}
+
+	// patchDocument is a Document without omitted fields, so json patches can replace and test
+	// fields that are empty.
+	patchDocument struct {
+		Slug       string            `json:"slug"`
+		Title      string            `json:"title"`
+		Difficulty string            `json:"difficulty"`
+		Tags       []string          `json:"tags"`
+		Content    string            `json:"content"`
+		Templates  map[string]string `json:"templates"`
+		TestCases  []patchTestCase   `json:"testCases"`
+		Editorial  DocumentEditorial `json:"editorial"`
+	}
+
+	patchTestCase struct {
+		ID          string `json:"id"`
+		Input       string `json:"input"`
+		Output      string `json:"output"`
+		Hidden      bool   `json:"hidden"`
+		Explanation string `json:"explanation"`
+		Weight      int    `json:"weight"`
+	}
+)
+
+const (
+	PatchMerge PatchType = "merge"
+	PatchJSON  PatchType = "json"
+)
+
+const (
+	FieldSlug       Field = "slug"
+	FieldTitle      Field = "title"
+	FieldContent    Field = "content"
+	FieldTemplates  Field = "templates"
+	FieldDifficulty Field = "difficulty"
+	FieldTags       Field = "tags"
+	FieldTestCases  Field = "testCases"
+	FieldEditorial  Field = "editorial"
+
+	// FieldStatus only changes through SetStatus, updates never write it.
+	FieldStatus Field = "status"
+)
+
+// Fields lists every field an update writes.
+var Fields = []Field{
+	FieldSlug, FieldTitle, FieldContent, FieldTemplates, FieldDifficulty, FieldTags, FieldTestCases, FieldEditorial,
+}
+
+// PatchTypeFromMediaType maps the Content-Type header of a PATCH request to its patch type.
+func PatchTypeFromMediaType(contentType string) (PatchType, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("invalid content type %q", contentType)
+	}
+
+	switch mediaType {
+	case "application/merge-patch+json":
+		return PatchMerge, nil
+	case "application/json-patch+json":
+		return PatchJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported content type %q, must be application/merge-patch+json or application/json-patch+json", mediaType)
+	}
+}
+
+// Apply returns a patched copy of q, q itself is left as it is. The copy is neither normalized
+// nor validated.
+func (p Patch) Apply(q *Algorithm) (*Algorithm, error) {
+	original, err := json.Marshal(newPatchDocument(q))
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch p.Type {
+	case PatchMerge:
+		patched, err = jsonpatch.MergePatch(original, p.Body)
+	case PatchJSON:
+		var ops jsonpatch.Patch
+		if ops, err = jsonpatch.DecodePatch(p.Body); err == nil {
+			patched, err = ops.Apply(original)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown patch type %q", ErrInvalidPatch, p.Type)
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	var doc patchDocument
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	result := doc.algorithm()
+	result.ID, result.CreatedAt, result.Version, result.Status = q.ID, q.CreatedAt, q.Version, q.Status
+	return result, nil
+}
+
+// ChangedFields lists the fields that differ between two versions of a question, empty and
+// missing values are alike.
+func ChangedFields(before, after *Algorithm) []Field {
+	pairs := []struct {
+		field         Field
+		before, after interface{}
+	}{
+		{FieldSlug, before.Slug, after.Slug},
+		{FieldTitle, before.Title, after.Title},
+		{FieldContent, before.Content, after.Content},
+		{FieldTemplates, before.Templates, after.Templates},
+		{FieldDifficulty, before.Difficulty, after.Difficulty},
+		{FieldTags, before.Tags, after.Tags},
+		{FieldTestCases, before.TestCases, after.TestCases},
+		{FieldEditorial, before.Editorial, after.Editorial},
+		{FieldStatus, before.Status, after.Status},
+	}
+
+	var fields []Field
+	for _, p := range pairs {
+		if !equalValues(p.before, p.after) {
+			fields = append(fields, p.field)
+		}
+	}
+	return fields
+}
+
+func equalValues(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Slice, reflect.Map:
+		if va.Len() == 0 && vb.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func newPatchDocument(q *Algorithm) patchDocument {
+	doc := NewDocument(q)
+	patchDoc := patchDocument{
+		Slug:       doc.Slug,
+		Title:      doc.Title,
+		Difficulty: doc.Difficulty,
+		Tags:       append([]string{}, doc.Tags...),
+		Content:    doc.Content,
+		Templates:  doc.Templates,
+		TestCases:  make([]patchTestCase, 0, len(doc.TestCases)),
+		Editorial:  doc.Editorial,
+	}
+	if patchDoc.Templates == nil {
+		patchDoc.Templates = map[string]string{}
+	}
+	for _, tc := range doc.TestCases {
+		patchDoc.TestCases = append(patchDoc.TestCases, patchTestCase(tc))
+	}
+	return patchDoc
+}
+
+func (d patchDocument) algorithm() *Algorithm {
+	doc := Document{
+		Slug:       d.Slug,
+		Title:      d.Title,
+		Difficulty: d.Difficulty,
+		Tags:       d.Tags,
+		Content:    d.Content,
+		Templates:  d.Templates,
+		Editorial:  d.Editorial,
+	}
+	for _, tc := range d.TestCases {
+		doc.TestCases = append(doc.TestCases, DocumentTestCase(tc))
+	}
+	return doc.Algorithm()
+}
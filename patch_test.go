@@ -0,0 +1,141 @@
+package question_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPatchableQuestion() *question.Algorithm {
+	return &question.Algorithm{
+		ID:         "1",
+		Slug:       "two-sum",
+		Title:      "Two Sum",
+		Content:    "Find two numbers.",
+		Templates:  map[question.Language]string{question.Go: "package main"},
+		Difficulty: question.Easy,
+		Tags:       []string{"array"},
+		TestCases: []question.TestCase{
+			{ID: "tc-1", Input: "1 2", Output: "3"},
+			{ID: "tc-2", Input: "2 2", Output: "4", Hidden: true},
+		},
+		CreatedAt: time.Date(2021, 11, 2, 0, 0, 0, 0, time.UTC),
+		Version:   4,
+	}
+}
+
+func TestPatch_Apply(t *testing.T) {
+	testCases := []struct {
+		scenario string
+		patch    question.Patch
+		expected func(q *question.Algorithm)
+	}{
+		{
+			scenario: "Given a merge patch it should change only the fields in the patch",
+			patch:    question.Patch{Type: question.PatchMerge, Body: []byte(`{"title":"Two Sum II","tags":["array","hash table"]}`)},
+			expected: func(q *question.Algorithm) {
+				q.Title, q.Tags = "Two Sum II", []string{"array", "hash table"}
+			},
+		},
+		{
+			scenario: "Given a merge patch with null it should clear the field",
+			patch:    question.Patch{Type: question.PatchMerge, Body: []byte(`{"templates":null,"slug":null}`)},
+			expected: func(q *question.Algorithm) {
+				q.Templates, q.Slug = nil, ""
+			},
+		},
+		{
+			scenario: "Given a json patch it should apply the operations in order",
+			patch: question.Patch{Type: question.PatchJSON, Body: []byte(`[
+				{"op":"test","path":"/testCases/1/hidden","value":true},
+				{"op":"replace","path":"/testCases/1/hidden","value":false},
+				{"op":"add","path":"/tags/-","value":"math"},
+				{"op":"add","path":"/templates/python","value":"print()"}
+			]`)},
+			expected: func(q *question.Algorithm) {
+				q.TestCases[1].Hidden = false
+				q.Tags = []string{"array", "math"}
+				q.Templates[question.Python] = "print()"
+			},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			given := newPatchableQuestion()
+			expected := newPatchableQuestion()
+			tC.expected(expected)
+
+			actual, err := tC.patch.Apply(given)
+
+			assert.Nil(t, err)
+			assert.Equal(t, expected, actual)
+			assert.Equal(t, newPatchableQuestion(), given)
+		})
+	}
+}
+
+func TestPatch_Apply_ReturnErr(t *testing.T) {
+	testCases := []struct {
+		scenario    string
+		patch       question.Patch
+		expectedErr error
+	}{
+		{
+			scenario:    "Given malformed json it should return ErrInvalidPatch",
+			patch:       question.Patch{Type: question.PatchMerge, Body: []byte(`{"title":`)},
+			expectedErr: question.ErrInvalidPatch,
+		},
+		{
+			scenario:    "Given an unknown field it should return ErrInvalidPatch",
+			patch:       question.Patch{Type: question.PatchMerge, Body: []byte(`{"titel":"Two Sum"}`)},
+			expectedErr: question.ErrInvalidPatch,
+		},
+		{
+			scenario:    "Given a path that does not exist it should return ErrInvalidPatch",
+			patch:       question.Patch{Type: question.PatchJSON, Body: []byte(`[{"op":"remove","path":"/testCases/5"}]`)},
+			expectedErr: question.ErrInvalidPatch,
+		},
+		{
+			scenario:    "Given a failing test operation it should return ErrConflict",
+			patch:       question.Patch{Type: question.PatchJSON, Body: []byte(`[{"op":"test","path":"/title","value":"Three Sum"}]`)},
+			expectedErr: question.ErrConflict,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			_, err := tC.patch.Apply(newPatchableQuestion())
+
+			assert.ErrorIs(t, err, tC.expectedErr)
+		})
+	}
+}
+
+func TestChangedFields(t *testing.T) {
+	before := newPatchableQuestion()
+	after := newPatchableQuestion()
+	after.Title = "Two Sum II"
+	after.TestCases[0].Weight = 2
+
+	assert.Empty(t, question.ChangedFields(before, newPatchableQuestion()))
+	assert.Equal(t, []question.Field{question.FieldTitle, question.FieldTestCases}, question.ChangedFields(before, after))
+
+	before.Tags, after.Tags = nil, []string{}
+	assert.Equal(t, []question.Field{question.FieldTitle, question.FieldTestCases}, question.ChangedFields(before, after))
+}
+
+func TestPatchTypeFromMediaType(t *testing.T) {
+	patchType, err := question.PatchTypeFromMediaType("application/merge-patch+json; charset=utf-8")
+	assert.Nil(t, err)
+	assert.Equal(t, question.PatchMerge, patchType)
+
+	patchType, err = question.PatchTypeFromMediaType("application/json-patch+json")
+	assert.Nil(t, err)
+	assert.Equal(t, question.PatchJSON, patchType)
+
+	_, err = question.PatchTypeFromMediaType("application/json")
+	assert.NotNil(t, err)
+}
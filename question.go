@@ -1,5 +1,15 @@
 package question
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
 type Difficulty string
 
 const (
@@ -8,26 +18,232 @@ const (
 	Hard   Difficulty = "hard"
 )
 
+const (
+	MaxTitleLength = 150
+	MaxTagLength   = 50
+)
+
+// AnyVersion as the version of a write applies it to whatever version is stored. The zero
+// version is never stored, so a version left unset fails instead of silently overwriting.
+const AnyVersion = -1
+
+var _slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
 type (
 	Algorithm struct {
 		ID         string
+		Slug       string
 		Title      string
 		Content    string
-		Template   string
+		Templates  map[Language]string
 		Difficulty Difficulty
+		Status     Status
 
 		Editorial Editorial
 
 		Tags      []string
 		TestCases []TestCase
+
+		CreatedAt time.Time
+
+		// Version counts the writes to a question, it starts at 1. Updates only apply when the
+		// question is still at their version, unless it is AnyVersion.
+		Version int
+
+		// DeletedAt is when the question was moved to the trash, zero for questions in use.
+		DeletedAt time.Time
+
+		// Score is the relevance to Filter.Query, only set on search results.
+		Score float64
 	}
 
+	// TestCase is either a sample shown to candidates as an example or a hidden one only used
+	// for judging. Weight is the score of passing it, zero counts as one.
 	TestCase struct {
-		Input  string
-		Output string
+		ID          string
+		Input       string
+		Output      string
+		Hidden      bool
+		Explanation string
+		Weight      int
 	}
 
 	Editorial struct {
 		Explanation string
 	}
 )
+
+func (d Difficulty) IsValid() bool {
+	return d == Easy || d == Medium || d == Hard
+}
+
+// Level orders difficulties from easy to hard, unknown difficulties come first.
+func (d Difficulty) Level() int {
+	switch d {
+	case Easy:
+		return 1
+	case Medium:
+		return 2
+	case Hard:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Normalize trims the title, derives the slug from it when missing, lowercases the difficulty
+// and tags, and drops empty or duplicate tags.
+func (q *Algorithm) Normalize() {
+	q.Title = strings.TrimSpace(q.Title)
+	q.Slug = strings.TrimSpace(q.Slug)
+	if q.Slug == "" {
+		q.Slug = Slugify(q.Title)
+	}
+	q.Difficulty = Difficulty(strings.ToLower(strings.TrimSpace(string(q.Difficulty))))
+	q.Tags = NormalizeTags(q.Tags)
+}
+
+// Validate reports every invalid field at once as a *ValidationError.
+func (q *Algorithm) Validate() error {
+	var verr ValidationError
+
+	if strings.TrimSpace(q.Title) == "" {
+		verr.Add("title", "must not be empty")
+	} else if l := utf8.RuneCountInString(q.Title); l > MaxTitleLength {
+		verr.Add("title", "must be at most %d characters, got %d", MaxTitleLength, l)
+	}
+
+	if q.Slug != "" && (!_slugPattern.MatchString(q.Slug) || len(q.Slug) > MaxTitleLength) {
+		verr.Add("slug", "must be at most %d lowercase letters, digits and single dashes", MaxTitleLength)
+	}
+
+	for lang := range q.Templates {
+		if !lang.IsValid() {
+			verr.Add(fmt.Sprintf("templates[%s]", lang), "unsupported language, must be one of %v", Languages)
+		}
+	}
+
+	if !q.Difficulty.IsValid() {
+		verr.Add("difficulty", "must be one of %s, %s, %s; got %q", Easy, Medium, Hard, q.Difficulty)
+	}
+
+	seen := make(map[string]bool, len(q.Tags))
+	for i, tag := range q.Tags {
+		field := fmt.Sprintf("tags[%d]", i)
+		switch {
+		case strings.TrimSpace(tag) == "":
+			verr.Add(field, "must not be empty")
+		case utf8.RuneCountInString(tag) > MaxTagLength:
+			verr.Add(field, "must be at most %d characters", MaxTagLength)
+		case seen[strings.ToLower(tag)]:
+			verr.Add(field, "duplicate tag %q", tag)
+		}
+		seen[strings.ToLower(tag)] = true
+	}
+
+	if len(q.TestCases) == 0 {
+		verr.Add("testCases", "at least one test case is required")
+	}
+	validateTestCases(&verr, q.TestCases)
+
+	return verr.Err()
+}
+
+// ValidateTestCases checks a full test case set on its own, as it is replaced apart from the question.
+func ValidateTestCases(testCases []TestCase) error {
+	var verr ValidationError
+	if len(testCases) == 0 {
+		verr.Add("testCases", "at least one test case is required")
+	}
+	validateTestCases(&verr, testCases)
+	return verr.Err()
+}
+
+func validateTestCases(verr *ValidationError, testCases []TestCase) {
+	seen := make(map[string]bool, len(testCases))
+	for i, tc := range testCases {
+		prefix := fmt.Sprintf("testCases[%d].", i)
+		tc.validate(verr, prefix)
+		if tc.ID != "" && seen[tc.ID] {
+			verr.Add(prefix+"id", "duplicate id %q", tc.ID)
+		}
+		seen[tc.ID] = true
+	}
+}
+
+func (tc *TestCase) Validate() error {
+	var verr ValidationError
+	tc.validate(&verr, "")
+	return verr.Err()
+}
+
+func (tc *TestCase) validate(verr *ValidationError, prefix string) {
+	if strings.TrimSpace(tc.Output) == "" {
+		verr.Add(prefix+"output", "must not be empty")
+	}
+	if tc.Weight < 0 {
+		verr.Add(prefix+"weight", "must not be negative")
+	}
+}
+
+// AssignTestCaseIDs gives every test case without an id a new one, so it can be addressed on its own.
+// It reports whether any id was assigned.
+func AssignTestCaseIDs(testCases []TestCase) bool {
+	assigned := false
+	for i := range testCases {
+		if testCases[i].ID == "" {
+			testCases[i].ID = uuid.NewString()
+			assigned = true
+		}
+	}
+	return assigned
+}
+
+func (tc TestCase) Points() int {
+	if tc.Weight == 0 {
+		return 1
+	}
+	return tc.Weight
+}
+
+func NormalizeTags(tags []string) []string {
+	var (
+		normalized []string
+		seen       = make(map[string]bool, len(tags))
+	)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// Slugify turns a title into a url friendly slug, e.g. "Two Sum II" into "two-sum-ii".
+func Slugify(title string) string {
+	var (
+		b    strings.Builder
+		dash bool
+	)
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > MaxTitleLength {
+		slug = strings.TrimRight(slug[:MaxTitleLength], "-")
+	}
+	return slug
+}
@@ -0,0 +1,102 @@
+package question_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		scenario       string
+		givenQuestion  question.Algorithm
+		expectedFields []string
+	}{
+		{
+			scenario: "Given valid question it should return nil",
+			givenQuestion: question.Algorithm{
+				Title:      "Two Sum",
+				Difficulty: question.Easy,
+				Tags:       []string{"array"},
+				TestCases:  []question.TestCase{{Input: "1 2", Output: "3"}},
+			},
+		},
+		{
+			scenario:       "Given empty question it should report title, difficulty and test cases",
+			givenQuestion:  question.Algorithm{},
+			expectedFields: []string{"title", "difficulty", "testCases"},
+		},
+		{
+			scenario: "Given too long title, unknown difficulty, duplicate tags and empty output it should report all of them",
+			givenQuestion: question.Algorithm{
+				Title:      strings.Repeat("a", question.MaxTitleLength+1),
+				Difficulty: "banana",
+				Tags:       []string{"tree", "Tree", ""},
+				TestCases:  []question.TestCase{{Input: "1", Output: "1"}, {Input: "2"}},
+			},
+			expectedFields: []string{"title", "difficulty", "tags[1]", "tags[2]", "testCases[1].output"},
+		},
+		{
+			scenario: "Given template of unsupported language it should report it",
+			givenQuestion: question.Algorithm{
+				Title:      "Two Sum",
+				Templates:  map[question.Language]string{question.Go: "package main", "cobol": "IDENTIFICATION DIVISION."},
+				Difficulty: question.Easy,
+				TestCases:  []question.TestCase{{Input: "1 2", Output: "3"}},
+			},
+			expectedFields: []string{"templates[cobol]"},
+		},
+		{
+			scenario: "Given malformed slug it should report slug",
+			givenQuestion: question.Algorithm{
+				Slug:       "Two Sum",
+				Title:      "Two Sum",
+				Difficulty: question.Easy,
+				TestCases:  []question.TestCase{{Input: "1 2", Output: "3"}},
+			},
+			expectedFields: []string{"slug"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			err := tC.givenQuestion.Validate()
+
+			if tC.expectedFields == nil {
+				assert.Nil(t, err)
+				return
+			}
+
+			var verr *question.ValidationError
+			assert.ErrorAs(t, err, &verr)
+
+			var actualFields []string
+			for _, f := range verr.Fields {
+				actualFields = append(actualFields, f.Field)
+			}
+			assert.Equal(t, tC.expectedFields, actualFields)
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	q := question.Algorithm{
+		Title:      "  Two Sum  ",
+		Difficulty: " Medium",
+		Tags:       []string{" Hash  Table", "array", "ARRAY", ""},
+	}
+
+	q.Normalize()
+
+	assert.Equal(t, "Two Sum", q.Title)
+	assert.Equal(t, "two-sum", q.Slug)
+	assert.Equal(t, question.Medium, q.Difficulty)
+	assert.Equal(t, []string{"hash table", "array"}, q.Tags)
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "two-sum-ii", question.Slugify("  Two Sum -- II! "))
+	assert.Equal(t, "lru-cache", question.Slugify("LRU_Cache"))
+	assert.Equal(t, "", question.Slugify("!!!"))
+}
@@ -0,0 +1,106 @@
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const _writers = 20
+
+func testConcurrentSaves(t *testing.T, r question.Repository) {
+	ids := make([]string, _writers)
+	errs := make([]error, _writers)
+	parallel(func(i int) {
+		ids[i], errs[i] = r.Save(context.Background(), NewQuestion(fmt.Sprintf("question-%d", i), question.Easy))
+	})
+
+	seen := make(map[string]bool, _writers)
+	for i, id := range ids {
+		require.Nil(t, errs[i])
+		assert.False(t, seen[id], "id %s handed out twice", id)
+		seen[id] = true
+	}
+	assert.Len(t, FindIDs(t, r, question.Filter{Limit: question.MaxLimit}), _writers)
+}
+
+// testConcurrentUpdates expects the question to end up as one of the writes, never a mix of them.
+func testConcurrentUpdates(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+
+	errs := make([]error, _writers)
+	parallel(func(i int) {
+		q := NewQuestion("two-sum", question.Easy, fmt.Sprintf("writer-%d", i))
+		q.Title = fmt.Sprintf("writer-%d", i)
+		errs[i] = r.Update(ctx, id, q)
+	})
+	for _, err := range errs {
+		require.Nil(t, err)
+	}
+
+	actual, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.Equal(t, []string{actual.Title}, actual.Tags)
+}
+
+// testConcurrentVersionedUpdates expects exactly one of the writers that read the same version to win.
+func testConcurrentVersionedUpdates(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+
+	errs := make([]error, _writers)
+	parallel(func(i int) {
+		q := NewQuestion("two-sum", question.Easy, fmt.Sprintf("writer-%d", i))
+		q.Version = 1
+		errs[i] = r.Update(ctx, id, q)
+	})
+
+	won := 0
+	for _, err := range errs {
+		if err == nil {
+			won++
+			continue
+		}
+		assert.ErrorIs(t, err, question.ErrPreconditionFailed)
+	}
+	assert.Equal(t, 1, won)
+
+	actual, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.Equal(t, 2, actual.Version)
+}
+
+func testConcurrentTestCaseAdds(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+
+	errs := make([]error, _writers)
+	parallel(func(i int) {
+		errs[i] = r.AddTestCase(ctx, id, question.TestCase{ID: fmt.Sprintf("added-%d", i), Output: "1"})
+	})
+	for _, err := range errs {
+		require.Nil(t, err)
+	}
+
+	actual, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.Len(t, actual.TestCases, 2+_writers)
+}
+
+func parallel(fn func(i int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < _writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,145 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSaveAndGet(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	given := NewQuestion("two-sum", question.Easy, "array")
+
+	id, err := r.Save(ctx, given)
+	require.Nil(t, err)
+	require.NotEmpty(t, id)
+
+	actual, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.Equal(t, id, actual.ID)
+	assert.False(t, actual.CreatedAt.IsZero())
+	assert.Equal(t, 1, actual.Version)
+	actual.ID, actual.CreatedAt, actual.Version = "", given.CreatedAt, given.Version
+	assert.Equal(t, given, actual)
+}
+
+func testFindBySlug(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+
+	actual, err := r.FindBySlug(ctx, "two-sum")
+	require.Nil(t, err)
+	assert.Equal(t, id, actual.ID)
+
+	_, err = r.FindBySlug(ctx, "three-sum")
+	assert.ErrorIs(t, err, question.ErrNotFound)
+}
+
+func testDuplicateSlug(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	Save(t, r, NewQuestion("two-sum", question.Easy))
+	other := Save(t, r, NewQuestion("three-sum", question.Easy))
+
+	_, err := r.Save(ctx, NewQuestion("two-sum", question.Hard))
+	assert.ErrorIs(t, err, question.ErrConflict)
+
+	err = r.Update(ctx, other, NewQuestion("two-sum", question.Hard))
+	assert.ErrorIs(t, err, question.ErrConflict)
+}
+
+func testUpdate(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy, "array"))
+	saved, err := r.Get(ctx, id)
+	require.Nil(t, err)
+
+	updated := NewQuestion("two-sum-ii", question.Medium, "two pointers")
+	updated.Templates = map[question.Language]string{question.Java: "class Main {}"}
+	require.Nil(t, r.Update(ctx, id, updated))
+
+	actual, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.Equal(t, saved.CreatedAt, actual.CreatedAt)
+	actual.ID, actual.CreatedAt = "", updated.CreatedAt
+	assert.Equal(t, updated, actual)
+}
+
+func testDelete(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+
+	require.Nil(t, r.Delete(ctx, id, question.AnyVersion))
+
+	_, err := r.Get(ctx, id)
+	assert.ErrorIs(t, err, question.ErrNotFound)
+}
+
+func testNotFound(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+	require.Nil(t, r.Delete(ctx, id, question.AnyVersion))
+
+	_, err := r.Get(ctx, id)
+	assert.ErrorIs(t, err, question.ErrNotFound)
+	assert.ErrorIs(t, r.Update(ctx, id, NewQuestion("two-sum", question.Easy)), question.ErrNotFound)
+	assert.ErrorIs(t, r.Delete(ctx, id, question.AnyVersion), question.ErrNotFound)
+	assert.ErrorIs(t, r.AddTestCase(ctx, id, question.TestCase{ID: "tc-3", Output: "1"}), question.ErrNotFound)
+	assert.ErrorIs(t, r.UpdateTestCase(ctx, id, question.TestCase{ID: "tc-1", Output: "1"}), question.ErrNotFound)
+	assert.ErrorIs(t, r.DeleteTestCase(ctx, id, "tc-1"), question.ErrNotFound)
+}
+
+func testInvalidID(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+
+	_, err := r.Get(ctx, "not-an-id")
+	assert.ErrorIs(t, err, question.ErrInvalidID)
+	assert.ErrorIs(t, r.Update(ctx, "not-an-id", NewQuestion("two-sum", question.Easy)), question.ErrInvalidID)
+	assert.ErrorIs(t, r.Delete(ctx, "not-an-id", question.AnyVersion), question.ErrInvalidID)
+	assert.ErrorIs(t, r.AddTestCase(ctx, "not-an-id", question.TestCase{ID: "tc-3", Output: "1"}), question.ErrInvalidID)
+}
+
+func testTestCases(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+
+	require.Nil(t, r.AddTestCase(ctx, id, question.TestCase{ID: "tc-3", Input: "3 3", Output: "6"}))
+	require.Nil(t, r.UpdateTestCase(ctx, id, question.TestCase{ID: "tc-1", Input: "1 1", Output: "2", Weight: 5}))
+	require.Nil(t, r.DeleteTestCase(ctx, id, "tc-2"))
+
+	assert.ErrorIs(t, r.UpdateTestCase(ctx, id, question.TestCase{ID: "tc-9", Output: "1"}), question.ErrTestCaseNotFound)
+	assert.ErrorIs(t, r.DeleteTestCase(ctx, id, "tc-9"), question.ErrTestCaseNotFound)
+
+	actual, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.Equal(t, []question.TestCase{
+		{ID: "tc-1", Input: "1 1", Output: "2", Weight: 5},
+		{ID: "tc-3", Input: "3 3", Output: "6"},
+	}, actual.TestCases)
+
+	require.Nil(t, r.DeleteTestCase(ctx, id, "tc-1"))
+	assert.ErrorIs(t, r.DeleteTestCase(ctx, id, "tc-3"), question.ErrLastTestCase)
+	actual, err = r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.Len(t, actual.TestCases, 1)
+}
+
+func testPartialUpdate(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy, "array"))
+	Save(t, r, NewQuestion("three-sum", question.Easy))
+	expected, err := r.Get(ctx, id)
+	require.Nil(t, err)
+
+	partial := &question.Algorithm{Title: "Two Sum", Tags: []string{"hash table"}, Content: "not written", Version: question.AnyVersion}
+	require.Nil(t, r.Update(ctx, id, partial, question.FieldTitle, question.FieldTags))
+
+	actual, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	expected.Title, expected.Tags, expected.Version = "Two Sum", []string{"hash table"}, 2
+	assert.Equal(t, expected, actual)
+
+	assert.ErrorIs(t, r.Update(ctx, id, &question.Algorithm{Slug: "three-sum", Version: question.AnyVersion}, question.FieldSlug), question.ErrConflict)
+}
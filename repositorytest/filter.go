@@ -0,0 +1,84 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFilterByTags(t *testing.T, r question.Repository) {
+	tree := Save(t, r, NewQuestion("tree", question.Easy, "tree"))
+	treeBFS := Save(t, r, NewQuestion("tree-bfs", question.Easy, "tree", "bfs"))
+	graph := Save(t, r, NewQuestion("graph", question.Easy, "graph", "bfs"))
+
+	testCases := []struct {
+		scenario string
+		filter   question.Filter
+		expected []string
+	}{
+		{"any tag", question.Filter{Tags: []string{"tree", "graph"}}, []string{tree, treeBFS, graph}},
+		{"all tags", question.Filter{Tags: []string{"tree", "bfs"}, TagMatch: question.TagMatchAll}, []string{treeBFS}},
+		{"no tag", question.Filter{Tags: []string{"tree"}, TagMatch: question.TagMatchNone}, []string{graph}},
+		{"excluded tags", question.Filter{Tags: []string{"bfs"}, ExcludeTags: []string{"graph"}}, []string{treeBFS}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			assert.Equal(t, tC.expected, FindIDs(t, r, tC.filter))
+		})
+	}
+}
+
+func testFilterByDifficulty(t *testing.T, r question.Repository) {
+	easy := Save(t, r, NewQuestion("easy", question.Easy))
+	Save(t, r, NewQuestion("medium", question.Medium))
+	hard := Save(t, r, NewQuestion("hard", question.Hard))
+
+	actual := FindIDs(t, r, question.Filter{Difficulties: []question.Difficulty{question.Easy, question.Hard}})
+
+	assert.Equal(t, []string{easy, hard}, actual)
+}
+
+func testSort(t *testing.T, r question.Repository) {
+	b := Save(t, r, NewQuestion("b", question.Hard))
+	c := Save(t, r, NewQuestion("c", question.Easy))
+	a := Save(t, r, NewQuestion("a", question.Medium))
+
+	assert.Equal(t, []string{b, c, a}, FindIDs(t, r, question.Filter{Sort: question.SortCreated}))
+	assert.Equal(t, []string{a, b, c}, FindIDs(t, r, question.Filter{Sort: question.SortTitle}))
+	assert.Equal(t, []string{c, a, b}, FindIDs(t, r, question.Filter{Sort: question.SortDifficulty}))
+}
+
+func testSearch(t *testing.T, r question.Repository) {
+	inTitle := NewQuestion("binary-search", question.Easy)
+	inTitle.Title = "Binary Search"
+	inContent := NewQuestion("rotated-array", question.Medium)
+	inContent.Content = "Use a binary search on the rotated array."
+	unrelated := NewQuestion("two-sum", question.Easy)
+
+	titleID := Save(t, r, inTitle)
+	contentID := Save(t, r, inContent)
+	Save(t, r, unrelated)
+
+	page, err := r.Find(context.Background(), normalized(question.Filter{Query: "binary"}))
+	require.Nil(t, err)
+
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, titleID, page.Items[0].ID)
+	assert.Equal(t, contentID, page.Items[1].ID)
+	assert.Greater(t, page.Items[0].Score, page.Items[1].Score)
+}
+
+func testFilterByTagsAndDifficulty(t *testing.T, r question.Repository) {
+	easyTree := Save(t, r, NewQuestion("easy-tree", question.Easy, "tree"))
+	Save(t, r, NewQuestion("hard-tree", question.Hard, "tree"))
+	Save(t, r, NewQuestion("easy-graph", question.Easy, "graph"))
+
+	assert.Equal(t, []string{easyTree}, FindIDs(t, r, question.Filter{
+		Tags:         []string{"tree"},
+		Difficulties: []question.Difficulty{question.Easy},
+	}))
+	assert.Len(t, FindIDs(t, r, question.Filter{}), 3)
+}
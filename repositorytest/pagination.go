@@ -0,0 +1,72 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPaginate walks every sort order page by page, ties included, and expects each question
+// exactly once and in the same order as a single page holding all of them.
+func testPaginate(t *testing.T, r question.Repository) {
+	for _, q := range []*question.Algorithm{
+		withTitle(NewQuestion("a", question.Hard), "Binary Search"),
+		withTitle(NewQuestion("b", question.Easy), "Binary Tree"),
+		withTitle(NewQuestion("c", question.Easy), "Binary Search"),
+		withTitle(NewQuestion("d", question.Medium), "Binary Heap Binary"),
+		withTitle(NewQuestion("e", question.Easy), "Binary Tree"),
+	} {
+		Save(t, r, q)
+	}
+
+	for _, sort := range []question.SortKey{question.SortCreated, question.SortTitle, question.SortDifficulty, question.SortRelevance} {
+		t.Run(string(sort), func(t *testing.T) {
+			f := question.Filter{Query: "binary", Sort: sort, Limit: question.MaxLimit}
+			all := FindIDs(t, r, f)
+			require.Len(t, all, 5)
+
+			var (
+				paged []string
+				pages int
+			)
+			f.Limit = 2
+			for {
+				page, err := r.Find(context.Background(), normalized(f))
+				require.Nil(t, err)
+				require.LessOrEqual(t, len(page.Items), 2)
+				for _, q := range page.Items {
+					paged = append(paged, q.ID)
+				}
+
+				if pages++; page.NextCursor == "" || pages > 5 {
+					break
+				}
+				f.Cursor = page.NextCursor
+			}
+
+			assert.Equal(t, 3, pages)
+			assert.Equal(t, all, paged)
+		})
+	}
+}
+
+func testInvalidCursor(t *testing.T, r question.Repository) {
+	Save(t, r, NewQuestion("two-sum", question.Easy))
+
+	for _, cursor := range []string{
+		"not a cursor",
+		question.Cursor{Sort: question.SortCreated, ID: "not-an-id"}.Encode(),
+		question.Cursor{Sort: question.SortTitle, ID: "000000000000000000000001"}.Encode(),
+	} {
+		_, err := r.Find(context.Background(), normalized(question.Filter{Cursor: cursor}))
+		assert.ErrorIs(t, err, question.ErrInvalidCursor)
+	}
+}
+
+func withTitle(q *question.Algorithm, title string) *question.Algorithm {
+	q.Title = title
+	return q
+}
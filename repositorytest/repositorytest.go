@@ -0,0 +1,118 @@
+// Package repositorytest defines what a correct question.Repository does. Every adapter runs
+// it from its own tests against fresh, empty repositories:
+//
+//	func TestRepository(t *testing.T) {
+//		repositorytest.Run(t, func(t *testing.T) question.Repository { return newEmptyRepository(t) })
+//	}
+//
+// The harness covers CRUD, not found and invalid id errors, single test case updates, tag,
+// difficulty, status and text filters, sorting, cursor pagination, versions, statuses, the
+// trash and concurrent writes.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// NewRepository returns an empty repository for a single test.
+	NewRepository func(t *testing.T) question.Repository
+
+	Test struct {
+		Name string
+		Run  func(t *testing.T, r question.Repository)
+	}
+)
+
+// Tests lists the conformance tests, adapters that cannot pass one yet may run the others on their own.
+var Tests = []Test{
+	{"SaveAndGet", testSaveAndGet},
+	{"FindBySlug", testFindBySlug},
+	{"DuplicateSlug", testDuplicateSlug},
+	{"Update", testUpdate},
+	{"PartialUpdate", testPartialUpdate},
+	{"Delete", testDelete},
+	{"Versions", testVersions},
+	{"StaleUpdate", testStaleUpdate},
+	{"StaleDelete", testStaleDelete},
+	{"SetStatus", testSetStatus},
+	{"Trash", testTrash},
+	{"Trashed", testTrashed},
+	{"Undelete", testUndelete},
+	{"TrashKeepsSlug", testTrashKeepsSlug},
+	{"Purge", testPurge},
+	{"NotFound", testNotFound},
+	{"InvalidID", testInvalidID},
+	{"TestCases", testTestCases},
+	{"FilterByTags", testFilterByTags},
+	{"FilterByDifficulty", testFilterByDifficulty},
+	{"FilterByTagsAndDifficulty", testFilterByTagsAndDifficulty},
+	{"FilterByStatus", testFilterByStatus},
+	{"Sort", testSort},
+	{"Search", testSearch},
+	{"Paginate", testPaginate},
+	{"InvalidCursor", testInvalidCursor},
+	{"ConcurrentSaves", testConcurrentSaves},
+	{"ConcurrentUpdates", testConcurrentUpdates},
+	{"ConcurrentVersionedUpdates", testConcurrentVersionedUpdates},
+	{"ConcurrentTestCaseAdds", testConcurrentTestCaseAdds},
+}
+
+// Run runs every conformance test against fresh repositories made by newRepository.
+func Run(t *testing.T, newRepository NewRepository) {
+	for _, tt := range Tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			tt.Run(t, newRepository(t))
+		})
+	}
+}
+
+// NewQuestion returns a valid published question, its title is derived from the slug.
+func NewQuestion(slug string, difficulty question.Difficulty, tags ...string) *question.Algorithm {
+	return &question.Algorithm{
+		Slug:       slug,
+		Title:      "Question " + slug,
+		Content:    "Content",
+		Templates:  map[question.Language]string{question.Go: "package main"},
+		Difficulty: difficulty,
+		Status:     question.StatusPublished,
+		Tags:       tags,
+		TestCases: []question.TestCase{
+			{ID: "tc-1", Input: "1 2", Output: "3", Explanation: "1 + 2"},
+			{ID: "tc-2", Input: "2 2", Output: "4", Hidden: true, Weight: 2},
+		},
+		Editorial: question.Editorial{Explanation: "Explanation"},
+		Version:   question.AnyVersion,
+	}
+}
+
+func Save(t *testing.T, r question.Repository, q *question.Algorithm) string {
+	t.Helper()
+
+	id, err := r.Save(context.Background(), q)
+	require.Nil(t, err)
+	return id
+}
+
+// FindIDs returns the ids of the first page of the filter, normalized like the service does.
+func FindIDs(t *testing.T, r question.Repository, f question.Filter) []string {
+	t.Helper()
+
+	page, err := r.Find(context.Background(), normalized(f))
+	require.Nil(t, err)
+
+	ids := []string{}
+	for _, q := range page.Items {
+		ids = append(ids, q.ID)
+	}
+	return ids
+}
+
+func normalized(f question.Filter) question.Filter {
+	f.Normalize()
+	return f
+}
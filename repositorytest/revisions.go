@@ -0,0 +1,128 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// NewRevisionRepository returns a revision repository without revisions for a single test.
+	NewRevisionRepository func(t *testing.T) question.RevisionRepository
+
+	RevisionTest struct {
+		Name string
+		Run  func(t *testing.T, r question.RevisionRepository)
+	}
+)
+
+// RevisionTests lists the conformance tests of revision repositories.
+var RevisionTests = []RevisionTest{
+	{"SaveAndGetRevision", testSaveAndGetRevision},
+	{"FindRevisions", testFindRevisions},
+	{"DuplicateRevision", testDuplicateRevision},
+	{"RevisionNotFound", testRevisionNotFound},
+	{"DeleteRevisions", testDeleteRevisions},
+}
+
+// RunRevisions runs every revision conformance test against fresh repositories made by newRepository.
+func RunRevisions(t *testing.T, newRepository NewRevisionRepository) {
+	for _, tt := range RevisionTests {
+		t.Run(tt.Name, func(t *testing.T) {
+			tt.Run(t, newRepository(t))
+		})
+	}
+}
+
+const _questionID = "5f8d0d55b54764421b7156c9"
+
+func newRevision(number int, title string) *question.Revision {
+	q := NewQuestion("two-sum", question.Easy, "array")
+	q.ID, q.Title, q.Version = _questionID, title, number
+	return &question.Revision{
+		QuestionID: _questionID,
+		Number:     number,
+		Author:     "ada",
+		Fields:     []question.Field{question.FieldTitle},
+		Diff:       []byte(`{"title":"` + title + `"}`),
+		Question:   *q,
+	}
+}
+
+func testSaveAndGetRevision(t *testing.T, r question.RevisionRepository) {
+	ctx := context.Background()
+	given := newRevision(1, "Two Sum")
+	require.Nil(t, r.SaveRevision(ctx, given))
+
+	actual, err := r.GetRevision(ctx, _questionID, 1)
+	require.Nil(t, err)
+	assert.False(t, actual.CreatedAt.IsZero())
+	assert.Equal(t, given.Question.Title, actual.Question.Title)
+	assert.Equal(t, given.Question.TestCases, actual.Question.TestCases)
+	assert.Equal(t, given.Question.Version, actual.Question.Version)
+	actual.CreatedAt, actual.Question = given.CreatedAt, given.Question
+	assert.Equal(t, given, actual)
+}
+
+func testFindRevisions(t *testing.T, r question.RevisionRepository) {
+	ctx := context.Background()
+	for _, rev := range []*question.Revision{newRevision(1, "One"), newRevision(3, "Three"), newRevision(2, "Two")} {
+		require.Nil(t, r.SaveRevision(ctx, rev))
+	}
+
+	revisions, err := r.FindRevisions(ctx, _questionID)
+	require.Nil(t, err)
+	var numbers []int
+	for _, rev := range revisions {
+		numbers = append(numbers, rev.Number)
+		assert.Nil(t, rev.Diff)
+		assert.Empty(t, rev.Question.Title)
+		assert.Equal(t, "ada", rev.Author)
+	}
+	assert.Equal(t, []int{3, 2, 1}, numbers)
+
+	latest, err := r.LatestRevision(ctx, _questionID)
+	require.Nil(t, err)
+	assert.Equal(t, "Three", latest.Question.Title)
+
+	revisions, err = r.FindRevisions(ctx, "5f8d0d55b54764421b7156ca")
+	require.Nil(t, err)
+	assert.Empty(t, revisions)
+}
+
+func testDuplicateRevision(t *testing.T, r question.RevisionRepository) {
+	ctx := context.Background()
+	require.Nil(t, r.SaveRevision(ctx, newRevision(1, "One")))
+
+	assert.ErrorIs(t, r.SaveRevision(ctx, newRevision(1, "Other")), question.ErrConflict)
+}
+
+func testRevisionNotFound(t *testing.T, r question.RevisionRepository) {
+	ctx := context.Background()
+	require.Nil(t, r.SaveRevision(ctx, newRevision(1, "One")))
+
+	_, err := r.GetRevision(ctx, _questionID, 2)
+	assert.ErrorIs(t, err, question.ErrRevisionNotFound)
+	_, err = r.LatestRevision(ctx, "5f8d0d55b54764421b7156ca")
+	assert.ErrorIs(t, err, question.ErrRevisionNotFound)
+	_, err = r.GetRevision(ctx, "not-an-id", 1)
+	assert.ErrorIs(t, err, question.ErrInvalidID)
+}
+
+func testDeleteRevisions(t *testing.T, r question.RevisionRepository) {
+	ctx := context.Background()
+	require.Nil(t, r.SaveRevision(ctx, newRevision(1, "One")))
+	require.Nil(t, r.SaveRevision(ctx, newRevision(2, "Two")))
+
+	require.Nil(t, r.DeleteRevisions(ctx, _questionID))
+	revisions, err := r.FindRevisions(ctx, _questionID)
+	require.Nil(t, err)
+	assert.Empty(t, revisions)
+	_, err = r.LatestRevision(ctx, _questionID)
+	assert.ErrorIs(t, err, question.ErrRevisionNotFound)
+
+	require.Nil(t, r.DeleteRevisions(ctx, _questionID))
+}
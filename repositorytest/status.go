@@ -0,0 +1,55 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSetStatus(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, withStatus(NewQuestion("two-sum", question.Easy), question.StatusDraft))
+
+	require.Nil(t, r.SetStatus(ctx, id, question.StatusInReview, 1))
+	actual, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.Equal(t, question.StatusInReview, actual.Status)
+	assert.Equal(t, 2, actual.Version)
+
+	assert.ErrorIs(t, r.SetStatus(ctx, id, question.StatusPublished, 1), question.ErrPreconditionFailed)
+	assert.ErrorIs(t, r.SetStatus(ctx, "5f8d0d55b54764421b7156ca", question.StatusPublished, question.AnyVersion), question.ErrNotFound)
+
+	// updates write the content of a question, never its status
+	require.Nil(t, r.Update(ctx, id, withStatus(NewQuestion("two-sum", question.Hard), question.StatusPublished)))
+	actual, err = r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.Equal(t, question.StatusInReview, actual.Status)
+	assert.Equal(t, question.Hard, actual.Difficulty)
+}
+
+func testFilterByStatus(t *testing.T, r question.Repository) {
+	draft := Save(t, r, withStatus(NewQuestion("two-sum", question.Easy), question.StatusDraft))
+	review := Save(t, r, withStatus(NewQuestion("three-sum", question.Easy), question.StatusInReview))
+	published := Save(t, r, NewQuestion("four-sum", question.Easy))
+
+	testCases := []struct {
+		statuses    []question.Status
+		expectedIDs []string
+	}{
+		{nil, []string{draft, review, published}},
+		{[]question.Status{question.StatusPublished}, []string{published}},
+		{[]question.Status{question.StatusDraft, question.StatusInReview}, []string{draft, review}},
+		{[]question.Status{question.StatusArchived}, []string{}},
+	}
+	for _, tC := range testCases {
+		assert.Equal(t, tC.expectedIDs, FindIDs(t, r, question.Filter{Statuses: tC.statuses}), "statuses %v", tC.statuses)
+	}
+}
+
+func withStatus(q *question.Algorithm, status question.Status) *question.Algorithm {
+	q.Status = status
+	return q
+}
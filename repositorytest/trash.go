@@ -0,0 +1,100 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTrash(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	deleted := Save(t, r, NewQuestion("two-sum", question.Easy, "array"))
+	kept := Save(t, r, NewQuestion("three-sum", question.Easy, "array"))
+	require.Nil(t, r.Delete(ctx, deleted, question.AnyVersion))
+
+	_, err := r.FindBySlug(ctx, "two-sum")
+	assert.ErrorIs(t, err, question.ErrNotFound)
+	assert.Equal(t, []string{kept}, FindIDs(t, r, question.Filter{Tags: []string{"array"}}))
+	assert.Equal(t, []string{deleted}, FindIDs(t, r, question.Filter{Tags: []string{"array"}, Deleted: true}))
+
+	page, err := r.Find(ctx, normalized(question.Filter{Deleted: true}))
+	require.Nil(t, err)
+	require.Len(t, page.Items, 1)
+	assert.False(t, page.Items[0].DeletedAt.IsZero())
+	assert.WithinDuration(t, time.Now(), page.Items[0].DeletedAt, time.Minute)
+}
+
+func testTrashed(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+
+	_, err := r.Trashed(ctx, id)
+	assert.ErrorIs(t, err, question.ErrNotFound)
+
+	require.Nil(t, r.Delete(ctx, id, question.AnyVersion))
+	actual, err := r.Trashed(ctx, id)
+	require.Nil(t, err)
+	assert.Equal(t, id, actual.ID)
+	assert.Equal(t, "two-sum", actual.Slug)
+	assert.False(t, actual.DeletedAt.IsZero())
+
+	_, err = r.Trashed(ctx, "5f8d0d55b54764421b7156ca")
+	assert.ErrorIs(t, err, question.ErrNotFound)
+	_, err = r.Trashed(ctx, "not-an-id")
+	assert.ErrorIs(t, err, question.ErrInvalidID)
+}
+
+func testUndelete(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+	require.Nil(t, r.Delete(ctx, id, question.AnyVersion))
+
+	require.Nil(t, r.Undelete(ctx, id))
+	actual, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.True(t, actual.DeletedAt.IsZero())
+	assert.Equal(t, 1, actual.Version)
+	assert.Empty(t, FindIDs(t, r, question.Filter{Deleted: true}))
+
+	assert.ErrorIs(t, r.Undelete(ctx, id), question.ErrNotFound)
+	assert.ErrorIs(t, r.Undelete(ctx, "5f8d0d55b54764421b7156ca"), question.ErrNotFound)
+	assert.ErrorIs(t, r.Undelete(ctx, "not-an-id"), question.ErrInvalidID)
+}
+
+// testTrashKeepsSlug expects a deleted question to hold on to its slug, so it can always be undeleted.
+func testTrashKeepsSlug(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+	require.Nil(t, r.Delete(ctx, id, question.AnyVersion))
+
+	_, err := r.Save(ctx, NewQuestion("two-sum", question.Easy))
+	assert.ErrorIs(t, err, question.ErrConflict)
+
+	_, err = r.Purge(ctx, time.Now().Add(time.Minute))
+	require.Nil(t, err)
+	Save(t, r, NewQuestion("two-sum", question.Easy))
+}
+
+func testPurge(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	deleted := Save(t, r, NewQuestion("two-sum", question.Easy))
+	kept := Save(t, r, NewQuestion("three-sum", question.Easy))
+	require.Nil(t, r.Delete(ctx, deleted, question.AnyVersion))
+
+	purged, err := r.Purge(ctx, time.Now().Add(-time.Hour))
+	require.Nil(t, err)
+	assert.Empty(t, purged)
+
+	purged, err = r.Purge(ctx, time.Now().Add(time.Minute))
+	require.Nil(t, err)
+	assert.Equal(t, []string{deleted}, purged)
+
+	assert.ErrorIs(t, r.Undelete(ctx, deleted), question.ErrNotFound)
+	assert.Empty(t, FindIDs(t, r, question.Filter{Deleted: true}))
+	_, err = r.Get(ctx, kept)
+	assert.Nil(t, err)
+}
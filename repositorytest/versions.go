@@ -0,0 +1,71 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codigician/question"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVersions expects every write to move the question to the next version.
+func testVersions(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+
+	q := NewQuestion("two-sum", question.Medium)
+	require.Nil(t, r.Update(ctx, id, q))
+	assert.Equal(t, 2, q.Version)
+
+	q.Title = "Two Sum"
+	require.Nil(t, r.Update(ctx, id, q))
+	assert.Equal(t, 3, q.Version)
+
+	require.Nil(t, r.AddTestCase(ctx, id, question.TestCase{ID: "tc-3", Output: "1"}))
+	require.Nil(t, r.UpdateTestCase(ctx, id, question.TestCase{ID: "tc-3", Output: "2"}))
+	require.Nil(t, r.DeleteTestCase(ctx, id, "tc-3"))
+
+	actual, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.Equal(t, 6, actual.Version)
+}
+
+func testStaleUpdate(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+	first, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	second, err := r.Get(ctx, id)
+	require.Nil(t, err)
+
+	first.Title = "First"
+	require.Nil(t, r.Update(ctx, id, first))
+	second.Title = "Second"
+	assert.ErrorIs(t, r.Update(ctx, id, second), question.ErrPreconditionFailed)
+
+	actual, err := r.Get(ctx, id)
+	require.Nil(t, err)
+	assert.Equal(t, "First", actual.Title)
+	assert.Equal(t, 2, actual.Version)
+
+	other := Save(t, r, NewQuestion("three-sum", question.Easy))
+	require.Nil(t, r.Delete(ctx, other, question.AnyVersion))
+	stale := NewQuestion("three-sum", question.Easy)
+	stale.Version = 1
+	assert.ErrorIs(t, r.Update(ctx, other, stale), question.ErrNotFound)
+}
+
+func testStaleDelete(t *testing.T, r question.Repository) {
+	ctx := context.Background()
+	id := Save(t, r, NewQuestion("two-sum", question.Easy))
+	require.Nil(t, r.Update(ctx, id, NewQuestion("two-sum", question.Medium)))
+
+	assert.ErrorIs(t, r.Delete(ctx, id, 1), question.ErrPreconditionFailed)
+	_, err := r.Get(ctx, id)
+	require.Nil(t, err)
+
+	require.Nil(t, r.Delete(ctx, id, 2))
+	_, err = r.Get(ctx, id)
+	assert.ErrorIs(t, err, question.ErrNotFound)
+}
@@ -0,0 +1,98 @@
+package question
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+)
+
+// _anonymous is the author of revisions of writes without a user in the context, which only
+// happen when the service is called directly rather than through the api or the cli.
+const _anonymous = "anonymous"
+
+type (
+	// Revision is an immutable record of a question as a single write left it.
+	Revision struct {
+		QuestionID string
+		// Number is the version of the question the revision recorded.
+		Number    int
+		Author    string
+		CreatedAt time.Time
+
+		// Fields lists what changed since the previous revision, Diff is the same change as a
+		// json merge patch of the question in its bundle form. The first revision of a question
+		// changes every field.
+		Fields []Field
+		Diff   []byte
+
+		Question Algorithm
+	}
+
+	RevisionRepository interface {
+		// SaveRevision fails with ErrConflict when the question already has a revision with that number.
+		SaveRevision(ctx context.Context, rev *Revision) error
+		// FindRevisions returns the revisions of a question newest first, without their
+		// question and diff.
+		FindRevisions(ctx context.Context, questionID string) ([]Revision, error)
+		// GetRevision returns ErrRevisionNotFound for numbers the question has no revision for.
+		GetRevision(ctx context.Context, questionID string, number int) (*Revision, error)
+		LatestRevision(ctx context.Context, questionID string) (*Revision, error)
+		DeleteRevisions(ctx context.Context, questionID string) error
+	}
+
+	// revisionDocument is the patch document with the status, so diffs show status changes too.
+	revisionDocument struct {
+		patchDocument
+		Status Status `json:"status,omitempty"`
+	}
+)
+
+// NewRevision records q as changed since prev, prev is nil for the first revision.
+func NewRevision(prev *Revision, q *Algorithm, author string) (*Revision, error) {
+	before, fields := []byte("{}"), Fields
+	if prev != nil {
+		var err error
+		if before, err = json.Marshal(newRevisionDocument(&prev.Question)); err != nil {
+			return nil, err
+		}
+		fields = ChangedFields(&prev.Question, q)
+	}
+
+	after, err := json.Marshal(newRevisionDocument(q))
+	if err != nil {
+		return nil, err
+	}
+	diff, err := jsonpatch.CreateMergePatch(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	if author == "" {
+		author = _anonymous
+	}
+	return &Revision{
+		QuestionID: q.ID,
+		Number:     q.Version,
+		Author:     author,
+		Fields:     fields,
+		Diff:       diff,
+		Question:   *q,
+	}, nil
+}
+
+func newRevisionDocument(q *Algorithm) revisionDocument {
+	return revisionDocument{newPatchDocument(q), q.Status}
+}
+
+// ParseRevisionNumber parses the number of a revision as given in urls.
+func ParseRevisionNumber(s string) (int, error) {
+	number, err := strconv.Atoi(s)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRevision, s)
+	}
+	return number, nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package sandbox
+
+import "syscall"
+
+func unshare(attr *syscall.SysProcAttr) {}
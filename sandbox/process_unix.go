@@ -0,0 +1,23 @@
+//go:build !windows
+// +build !windows
+
+package sandbox
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// isolate starts the command in its own process group, so kill reaches its children too, and as
+// the account when there is one.
+func isolate(cmd *exec.Cmd, as *account) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if as != nil {
+		cmd.SysProcAttr.Credential = &syscall.Credential{Uid: uint32(as.uid), Gid: uint32(as.gid)}
+		unshare(cmd.SysProcAttr)
+	}
+}
+
+func kill(cmd *exec.Cmd) {
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,12 @@
+//go:build windows
+// +build windows
+
+package sandbox
+
+import "os/exec"
+
+func isolate(cmd *exec.Cmd, as *account) {}
+
+func kill(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
@@ -0,0 +1,414 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/codigician/question"
+)
+
+const (
+	_compileTimeout = 30 * time.Second
+	_maxErrorLength = 1024
+)
+
+type (
+	Limits struct {
+		Time time.Duration
+		// Memory limits the data segment in bytes, i.e. the heap and every other private writable
+		// mapping, zero means unlimited. Unlike the address space it leaves alone the virtual memory
+		// runtimes like node and the jvm reserve up front without using it.
+		Memory int64
+		// Output is the maximum number of bytes read from stdout and stderr.
+		Output int
+		// Submission bounds judging a whole submission, compilation included, zero means unlimited.
+		// Cases left without time are reported as time limit exceeded, keep it below the write
+		// timeout of the server so the verdict reaches the client.
+		Submission time.Duration
+		// Concurrent is how many submissions are judged at once, others fail with
+		// question.ErrJudgeBusy. Zero means unlimited.
+		Concurrent int
+		// Processes caps the processes and threads of each user running submissions, zero means
+		// unlimited. It only applies WithUser, the processes of the server would count otherwise.
+		Processes int
+	}
+
+	// Toolchain describes how to build and run code of a language. Commands run inside
+	// the working directory the source file is written to.
+	Toolchain struct {
+		Source  string
+		Compile []string
+		Run     []string
+		// Env is added to the environment of the compile commands, runs never see it.
+		Env []string
+		// Cache is the build cache directory the compile commands share, only the user compiling
+		// can access it.
+		Cache string
+		// Warmup is a program Warm compiles, so the first submissions do not start with a cold cache.
+		Warmup string
+	}
+
+	// Runner judges submissions by running them as local processes. Each submission gets
+	// its own temporary directory, an empty environment apart from PATH, HOME and, to compile,
+	// the variables of its toolchain, and a process group that is killed as a whole once the time
+	// limit is exceeded. Without WithUser submissions run as the user of the server and can read
+	// what it can, run it in a container dedicated to judging then.
+	Runner struct {
+		limits     Limits
+		toolchains map[question.Language]Toolchain
+		account    *account
+		// slots hands out the numbers of the submissions judged at once, a number picks the
+		// user a submission runs as.
+		slots chan int
+	}
+
+	Option func(r *Runner)
+
+	// account is the user and group submissions compile or run as.
+	account struct {
+		uid, gid int
+	}
+
+	// limitedBuffer does not embed bytes.Buffer on purpose, its ReadFrom would let io.Copy bypass the limit.
+	limitedBuffer struct {
+		buf   bytes.Buffer
+		limit int
+	}
+)
+
+// DefaultToolchains returns the toolchains of the supported languages, the ones with a build cache
+// share it between submissions in cacheDir.
+func DefaultToolchains(cacheDir string) map[question.Language]Toolchain {
+	goCache := filepath.Join(cacheDir, "go")
+	return map[question.Language]Toolchain{
+		question.Go: {
+			Source:  "main.go",
+			Compile: []string{"go", "build", "-o", "main", "main.go"},
+			Run:     []string{"./main"},
+			// a cold cache builds the standard library first, which takes longer than most requests may
+			Env:    []string{"GOCACHE=" + goCache},
+			Cache:  goCache,
+			Warmup: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println()\n}\n",
+		},
+		question.Python: {
+			Source: "main.py",
+			Run:    []string{"python3", "main.py"},
+		},
+		question.Java: {
+			Source:  "Main.java",
+			Compile: []string{"javac", "Main.java"},
+			// a small initial heap, the default one grows with the memory of the host
+			Run: []string{"java", "-Xss64m", "-Xms32m", "-XX:+UseSerialGC", "-cp", ".", "Main"},
+		},
+		question.Cpp: {
+			Source:  "main.cpp",
+			Compile: []string{"g++", "-O2", "-std=c++17", "-o", "main", "main.cpp"},
+			Run:     []string{"./main"},
+		},
+		question.JavaScript: {
+			Source: "main.js",
+			Run:    []string{"node", "main.js"},
+		},
+	}
+}
+
+func NewRunner(limits Limits, toolchains map[question.Language]Toolchain, opts ...Option) *Runner {
+	r := &Runner{limits: limits, toolchains: toolchains}
+	if limits.Concurrent > 0 {
+		r.slots = make(chan int, limits.Concurrent)
+		for slot := 0; slot < limits.Concurrent; slot++ {
+			r.slots <- slot
+		}
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// WithUser compiles submissions as the given user and group, the only user with access to the
+// build caches, and runs them as the users after it, one per submission judged at once up to
+// uid+Limits.Concurrent, so they neither read nor slow down each other. Without a concurrency
+// limit they all run as uid+1. On linux they also run in their own network, pid, ipc and uts
+// namespaces, so they neither read the files and processes of the server nor reach other hosts.
+// The server has to run as root for it.
+func WithUser(uid, gid int) Option {
+	return func(r *Runner) {
+		r.account = &account{uid, gid}
+	}
+}
+
+func (r *Runner) Judge(ctx context.Context, sub question.Submission, testCases []question.TestCase) ([]question.CaseResult, error) {
+	toolchain, ok := r.toolchains[sub.Language]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", question.ErrUnsupportedLanguage, sub.Language)
+	}
+
+	slot := 0
+	if r.slots != nil {
+		select {
+		case slot = <-r.slots:
+			defer func() { r.slots <- slot }()
+		default:
+			return nil, question.ErrJudgeBusy
+		}
+	}
+
+	if r.limits.Submission > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.limits.Submission)
+		defer cancel()
+	}
+
+	if err := r.prepareCache(toolchain.Cache); err != nil {
+		return nil, err
+	}
+	dir, err := r.writeSource(toolchain, sub.Code)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	results := make([]question.CaseResult, 0, len(testCases))
+
+	if res := r.compile(ctx, dir, toolchain); res.err != nil {
+		for range testCases {
+			results = append(results, question.CaseResult{Verdict: question.CompilationError, Error: res.stderr})
+		}
+		return results, nil
+	}
+	runAs := r.runAccount(slot)
+	if err := handOver(dir, runAs); err != nil {
+		return nil, err
+	}
+
+	for _, tc := range testCases {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			results = append(results, question.CaseResult{Verdict: question.TimeLimitExceeded})
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		res := r.run(ctx, dir, toolchain.Run, nil, tc.Input, r.limits, runAs)
+		result := question.CaseResult{Output: res.stdout, Duration: res.duration}
+		switch {
+		case res.timedOut:
+			result.Verdict = question.TimeLimitExceeded
+		case res.err != nil:
+			result.Verdict = question.RuntimeError
+			result.Error = res.stderr
+		case !outputMatches(tc.Output, res.stdout):
+			result.Verdict = question.WrongAnswer
+		default:
+			result.Verdict = question.Accepted
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Warm compiles the warmup program of every toolchain that has one, to fill their build caches.
+func (r *Runner) Warm(ctx context.Context) error {
+	for lang, toolchain := range r.toolchains {
+		if toolchain.Warmup == "" {
+			continue
+		}
+
+		if err := r.prepareCache(toolchain.Cache); err != nil {
+			return err
+		}
+		dir, err := r.writeSource(toolchain, toolchain.Warmup)
+		if err != nil {
+			return err
+		}
+		res := r.compile(ctx, dir, toolchain)
+		os.RemoveAll(dir)
+		if res.err != nil {
+			return fmt.Errorf("warm %s: %v: %s", lang, res.err, res.stderr)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) compile(ctx context.Context, dir string, toolchain Toolchain) runResult {
+	if len(toolchain.Compile) == 0 {
+		return runResult{}
+	}
+
+	res := r.run(ctx, dir, toolchain.Compile, toolchain.Env, "", Limits{Time: _compileTimeout}, r.account)
+	if res.timedOut {
+		res.stderr = "compilation exceeded the time limit"
+	}
+	return res
+}
+
+// prepareCache creates the build cache directory, accessible only by the user compiling.
+func (r *Runner) prepareCache(cache string) error {
+	if cache == "" {
+		return nil
+	}
+	if err := os.MkdirAll(cache, 0o755); err != nil {
+		return err
+	}
+	if r.account != nil {
+		if err := os.Chown(cache, r.account.uid, r.account.gid); err != nil {
+			return err
+		}
+	}
+	return os.Chmod(cache, 0o700)
+}
+
+// runAccount is the user the submission judged in the given slot runs as, nil without WithUser.
+func (r *Runner) runAccount(slot int) *account {
+	if r.account == nil {
+		return nil
+	}
+	return &account{uid: r.account.uid + 1 + slot, gid: r.account.gid}
+}
+
+// handOver gives the working directory and everything compiled into it to the user the submission
+// runs as, the directory stays accessible by its owner only.
+func handOver(dir string, as *account) error {
+	if as == nil {
+		return nil
+	}
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Lchown(path, as.uid, as.gid)
+	})
+}
+
+// writeSource writes the code to the source file of the toolchain in a new temporary directory,
+// which only its owner can access, both owned by the user compiling submissions.
+func (r *Runner) writeSource(toolchain Toolchain, code string) (string, error) {
+	dir, err := os.MkdirTemp("", "submission-")
+	if err != nil {
+		return "", err
+	}
+
+	source := filepath.Join(dir, toolchain.Source)
+	err = os.WriteFile(source, []byte(code), 0o600)
+	if err == nil && r.account != nil {
+		if err = os.Chown(dir, r.account.uid, r.account.gid); err == nil {
+			err = os.Chown(source, r.account.uid, r.account.gid)
+		}
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+type runResult struct {
+	stdout   string
+	stderr   string
+	duration time.Duration
+	timedOut bool
+	err      error
+}
+
+// run runs the command as the account, if there is one, until it exits or the timeout is over,
+// the deadline of ctx counts as a timeout as well.
+func (r *Runner) run(ctx context.Context, dir string, command, env []string, stdin string, limits Limits, as *account) runResult {
+	var ulimits []string
+	if limits.Memory > 0 {
+		ulimits = append(ulimits, fmt.Sprintf("ulimit -d %d", limits.Memory/1024))
+	}
+	if limits.Processes > 0 && as != nil {
+		// dash calls the process limit -p, bash and busybox -u
+		ulimits = append(ulimits, fmt.Sprintf("{ ulimit -u %[1]d 2>/dev/null || ulimit -p %[1]d; }", limits.Processes))
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	if len(ulimits) > 0 {
+		// ulimit applies to the shell and is inherited by the command it execs into
+		script := strings.Join(append(ulimits, `exec "$@"`), " && ")
+		cmd = exec.Command("sh", append([]string{"-c", script, "sh"}, command...)...)
+	}
+
+	stdout := &limitedBuffer{limit: r.limits.Output}
+	stderr := &limitedBuffer{limit: _maxErrorLength}
+	cmd.Dir = dir
+	cmd.Env = append([]string{"PATH=" + os.Getenv("PATH"), "HOME=" + dir}, env...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	isolate(cmd, as)
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return runResult{err: err, stderr: err.Error()}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	timer := time.NewTimer(limits.Time)
+	defer timer.Stop()
+
+	res := runResult{}
+	select {
+	case res.err = <-done:
+	case <-timer.C:
+		kill(cmd)
+		res.err = <-done
+		res.timedOut = true
+	case <-ctx.Done():
+		kill(cmd)
+		res.err = <-done
+		res.timedOut = errors.Is(ctx.Err(), context.DeadlineExceeded)
+	}
+
+	res.duration = time.Since(start)
+	res.stdout = stdout.String()
+	res.stderr = stderr.String()
+
+	var exitErr *exec.ExitError
+	if res.err != nil && !errors.As(res.err, &exitErr) && res.stderr == "" {
+		res.stderr = res.err.Error()
+	}
+	return res
+}
+
+// outputMatches compares outputs line by line ignoring trailing whitespace and trailing empty lines.
+func outputMatches(expected, actual string) bool {
+	return normalizeOutput(expected) == normalizeOutput(actual)
+}
+
+func normalizeOutput(output string) string {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); b.limit > 0 && len(p) > remaining {
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		// pretend the write succeeded so the process is not killed by a broken pipe
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
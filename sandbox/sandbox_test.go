@@ -0,0 +1,263 @@
+package sandbox_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/codigician/question"
+	"github.com/codigician/question/sandbox"
+	"github.com/stretchr/testify/assert"
+)
+
+const _shell question.Language = "sh"
+
+func TestJudge(t *testing.T) {
+	testCases := []question.TestCase{
+		{Input: "1 2", Output: "3"},
+		{Input: "2 2", Output: "4\n"},
+	}
+
+	scenarios := []struct {
+		scenario         string
+		code             string
+		expectedVerdicts []question.Verdict
+	}{
+		{
+			scenario:         "Given correct solution it should accept every case",
+			code:             `read a b; echo "$((a + b))  "`,
+			expectedVerdicts: []question.Verdict{question.Accepted, question.Accepted},
+		},
+		{
+			scenario:         "Given solution wrong for some cases it should report wrong answer for them",
+			code:             `read a b; echo "$((a * b))"`,
+			expectedVerdicts: []question.Verdict{question.WrongAnswer, question.Accepted},
+		},
+		{
+			scenario:         "Given crashing solution it should report runtime error",
+			code:             `echo boom >&2; exit 3`,
+			expectedVerdicts: []question.Verdict{question.RuntimeError, question.RuntimeError},
+		},
+		{
+			scenario:         "Given solution exceeding the time limit it should report time limit exceeded",
+			code:             `sleep 5 & wait`,
+			expectedVerdicts: []question.Verdict{question.TimeLimitExceeded, question.TimeLimitExceeded},
+		},
+		{
+			scenario:         "Given solution that does not compile it should report compilation error",
+			code:             `if then fi (`,
+			expectedVerdicts: []question.Verdict{question.CompilationError, question.CompilationError},
+		},
+	}
+	for _, s := range scenarios {
+		t.Run(s.scenario, func(t *testing.T) {
+			runner := newShellRunner(sandbox.Limits{Time: 500 * time.Millisecond})
+
+			start := time.Now()
+			results, err := runner.Judge(context.Background(), question.Submission{Language: _shell, Code: s.code}, testCases)
+
+			assert.Nil(t, err)
+			assert.Less(t, time.Since(start), 3*time.Second)
+
+			var actualVerdicts []question.Verdict
+			for _, r := range results {
+				actualVerdicts = append(actualVerdicts, r.Verdict)
+			}
+			assert.Equal(t, s.expectedVerdicts, actualVerdicts)
+		})
+	}
+}
+
+func TestJudge_RuntimeError_ReportStderr(t *testing.T) {
+	runner := newShellRunner(sandbox.Limits{Time: time.Second})
+
+	results, err := runner.Judge(context.Background(),
+		question.Submission{Language: _shell, Code: "echo boom >&2; exit 1"},
+		[]question.TestCase{{Output: "1"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "boom\n", results[0].Error)
+}
+
+func TestJudge_MemoryLimit_AppliedToProcess(t *testing.T) {
+	runner := newShellRunner(sandbox.Limits{Time: time.Second, Memory: 512 * 1024 * 1024})
+
+	results, err := runner.Judge(context.Background(),
+		question.Submission{Language: _shell, Code: "ulimit -d"},
+		[]question.TestCase{{Output: "524288"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, question.Accepted, results[0].Verdict)
+}
+
+func TestJudge_MemoryLimit_RuntimesStart(t *testing.T) {
+	programs := map[question.Language]string{
+		question.JavaScript: `console.log("ok")`,
+		question.Python:     `print("ok")`,
+	}
+	toolchains := sandbox.DefaultToolchains(t.TempDir())
+	runner := sandbox.NewRunner(sandbox.Limits{Time: 5 * time.Second, Memory: 256 << 20}, toolchains)
+
+	for lang, code := range programs {
+		t.Run(string(lang), func(t *testing.T) {
+			if _, err := exec.LookPath(toolchains[lang].Run[0]); err != nil {
+				t.Skipf("%s is not installed", lang)
+			}
+
+			results, err := runner.Judge(context.Background(), question.Submission{Language: lang, Code: code}, []question.TestCase{{Output: "ok"}})
+
+			assert.Nil(t, err)
+			assert.Equal(t, question.Accepted, results[0].Verdict, results[0].Error)
+		})
+	}
+}
+
+func TestJudge_OutputLimit_TruncateOutput(t *testing.T) {
+	runner := newShellRunner(sandbox.Limits{Time: time.Second, Output: 4})
+
+	results, err := runner.Judge(context.Background(),
+		question.Submission{Language: _shell, Code: "echo 123456789"},
+		[]question.TestCase{{Output: "123456789"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, question.WrongAnswer, results[0].Verdict)
+	assert.Equal(t, "1234", results[0].Output)
+}
+
+func TestJudge_SubmissionLimit_ReportRemainingCasesAsTimeLimitExceeded(t *testing.T) {
+	runner := newShellRunner(sandbox.Limits{Time: time.Second, Submission: 300 * time.Millisecond})
+
+	start := time.Now()
+	results, err := runner.Judge(context.Background(),
+		question.Submission{Language: _shell, Code: "sleep 0.2; echo 1"},
+		[]question.TestCase{{Output: "1"}, {Output: "1"}, {Output: "1"}})
+
+	assert.Nil(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, question.Accepted, results[0].Verdict)
+	assert.Equal(t, question.TimeLimitExceeded, results[1].Verdict)
+	assert.Equal(t, question.TimeLimitExceeded, results[2].Verdict)
+}
+
+func TestJudge_Concurrent_RejectSubmissionsOverTheLimit(t *testing.T) {
+	runner := newShellRunner(sandbox.Limits{Time: time.Second, Concurrent: 1})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = runner.Judge(context.Background(), question.Submission{Language: _shell, Code: "sleep 0.5"}, []question.TestCase{{Output: "1"}})
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	_, err := runner.Judge(context.Background(), question.Submission{Language: _shell, Code: "echo 1"}, []question.TestCase{{Output: "1"}})
+	assert.ErrorIs(t, err, question.ErrJudgeBusy)
+
+	<-done
+	results, err := runner.Judge(context.Background(), question.Submission{Language: _shell, Code: "echo 1"}, []question.TestCase{{Output: "1"}})
+	assert.Nil(t, err)
+	assert.Equal(t, question.Accepted, results[0].Verdict)
+}
+
+func TestJudge_WithUser_IsolateSubmission(t *testing.T) {
+	if runtime.GOOS != "linux" || os.Geteuid() != 0 {
+		t.Skip("running as another user in new namespaces needs root on linux")
+	}
+	const judge = 60000
+	runner := sandbox.NewRunner(sandbox.Limits{Time: time.Second, Processes: 16}, shellToolchains(), sandbox.WithUser(judge, judge))
+
+	results, err := runner.Judge(context.Background(), question.Submission{Language: _shell, Code: `
+id -u
+stat -c "%u %a" .
+cut -d: -f1 /proc/net/dev | tail -n +3 | tr -d " "
+cat /proc/1/environ > /dev/null 2>&1 || echo environ denied
+ulimit -u 2> /dev/null || ulimit -p
+`}, []question.TestCase{{Output: "60001\n60001 700\nlo\nenviron denied\n16"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, question.Accepted, results[0].Verdict, results[0].Output+results[0].Error)
+}
+
+func TestJudge_WithUser_KeepBuildCacheFromSubmissions(t *testing.T) {
+	if runtime.GOOS != "linux" || os.Geteuid() != 0 {
+		t.Skip("running as another user in new namespaces needs root on linux")
+	}
+	const judge = 60000
+	// the user compiling has to reach the cache through the test directories
+	root := t.TempDir()
+	assert.Nil(t, os.Chmod(filepath.Dir(root), 0o755))
+	assert.Nil(t, os.Chmod(root, 0o755))
+	cache := filepath.Join(root, "cache")
+	toolchain := sandbox.Toolchain{
+		Source:  "main.sh",
+		Compile: []string{"sh", "-c", `sh -n main.sh && id -u > "$CACHE/compiled-by"`},
+		Run:     []string{"sh", "main.sh"},
+		Env:     []string{"CACHE=" + cache},
+		Cache:   cache,
+	}
+	runner := sandbox.NewRunner(sandbox.Limits{Time: time.Second, Concurrent: 2},
+		map[question.Language]sandbox.Toolchain{_shell: toolchain}, sandbox.WithUser(judge, judge))
+
+	code := fmt.Sprintf(`echo "${CACHE:-no env}"; ls %s > /dev/null 2>&1 || echo cache denied`, cache)
+	results, err := runner.Judge(context.Background(), question.Submission{Language: _shell, Code: code},
+		[]question.TestCase{{Output: "no env\ncache denied"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, question.Accepted, results[0].Verdict, results[0].Output+results[0].Error)
+	compiledBy, err := os.ReadFile(filepath.Join(cache, "compiled-by"))
+	assert.Nil(t, err)
+	assert.Equal(t, "60000\n", string(compiledBy))
+
+	// submissions judged at once run as different users
+	uids := make(chan string, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			results, _ := runner.Judge(context.Background(), question.Submission{Language: _shell, Code: "sleep 0.3; id -u"}, []question.TestCase{{}})
+			uids <- results[0].Output
+		}()
+	}
+	assert.ElementsMatch(t, []string{"60001\n", "60002\n"}, []string{<-uids, <-uids})
+}
+
+func TestWarm(t *testing.T) {
+	cache := t.TempDir()
+	toolchain := sandbox.Toolchain{
+		Source:  "main.sh",
+		Compile: []string{"sh", "-c", `sh -n main.sh && echo warm > "$CACHE/warm"`},
+		Env:     []string{"CACHE=" + cache},
+		Warmup:  "echo",
+	}
+	runner := sandbox.NewRunner(sandbox.Limits{Time: time.Second}, map[question.Language]sandbox.Toolchain{_shell: toolchain})
+
+	assert.Nil(t, runner.Warm(context.Background()))
+	assert.FileExists(t, filepath.Join(cache, "warm"))
+
+	toolchain.Warmup = "if then fi ("
+	runner = sandbox.NewRunner(sandbox.Limits{Time: time.Second}, map[question.Language]sandbox.Toolchain{_shell: toolchain})
+	assert.NotNil(t, runner.Warm(context.Background()))
+}
+
+func TestJudge_UnsupportedLanguage_ReturnErr(t *testing.T) {
+	runner := newShellRunner(sandbox.Limits{Time: time.Second})
+
+	_, err := runner.Judge(context.Background(), question.Submission{Language: "cobol", Code: "x"}, nil)
+
+	assert.ErrorIs(t, err, question.ErrUnsupportedLanguage)
+}
+
+func newShellRunner(limits sandbox.Limits) *sandbox.Runner {
+	return sandbox.NewRunner(limits, shellToolchains())
+}
+
+func shellToolchains() map[question.Language]sandbox.Toolchain {
+	return map[question.Language]sandbox.Toolchain{
+		_shell: {
+			Source:  "main.sh",
+			Compile: []string{"sh", "-n", "main.sh"},
+			Run:     []string{"sh", "main.sh"},
+		},
+	}
+}
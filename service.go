@@ -1,48 +1,649 @@
 package question
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// _patchAttempts bounds how often a patch without a version is applied again after losing a race.
+const _patchAttempts = 3
+
+// _updateFields are the fields Update writes, test cases are only changed on their own.
+var _updateFields = []Field{FieldSlug, FieldTitle, FieldContent, FieldTemplates, FieldDifficulty, FieldTags, FieldEditorial}
 
 type (
 	Repository interface {
 		Get(ctx context.Context, id string) (*Algorithm, error)
+		FindBySlug(ctx context.Context, slug string) (*Algorithm, error)
 		Save(ctx context.Context, q *Algorithm) (string, error)
-		Find(ctx context.Context, tags []string, difficulty Difficulty) ([]Algorithm, error)
-		Update(ctx context.Context, id string, q *Algorithm) error
-		Delete(ctx context.Context, id string) error
+		Find(ctx context.Context, f Filter) (*Page, error)
+
+		// Update and Delete fail with ErrPreconditionFailed when the question is no longer at the
+		// given version, AnyVersion skips the check. Update sets q.Version to the version it wrote.
+		// Update writes the given fields of q, every field when none are given.
+		Update(ctx context.Context, id string, q *Algorithm, fields ...Field) error
+		// SetStatus moves the question to a status as its next version, updates keep the status
+		// as it is. It checks the version like Update.
+		SetStatus(ctx context.Context, id string, status Status, version int) error
+
+		// Delete moves the question to the trash, where every other method but Find with
+		// Filter.Deleted and Trashed takes it for not found. It keeps its slug until it is purged.
+		// Trashed gets a question in the trash, Undelete takes it out of the trash, Purge removes
+		// the questions deleted before the given time for good and returns their ids.
+		Delete(ctx context.Context, id string, version int) error
+		Trashed(ctx context.Context, id string) (*Algorithm, error)
+		Undelete(ctx context.Context, id string) error
+		Purge(ctx context.Context, before time.Time) ([]string, error)
+
+		// AddTestCase, UpdateTestCase and DeleteTestCase change a single test case of a question
+		// without rewriting the others, each of them is a new version of the question.
+		// DeleteTestCase fails with ErrLastTestCase instead of leaving a question without any.
+		AddTestCase(ctx context.Context, id string, tc TestCase) error
+		UpdateTestCase(ctx context.Context, id string, tc TestCase) error
+		DeleteTestCase(ctx context.Context, id, testCaseID string) error
 	}
 
 	QuestionService struct {
 		repository Repository
-	}
-
-	Filter struct {
-		Tags       []string
-		Difficulty Difficulty
+		judge      Judge
+		revisions  RevisionRepository
 	}
 )
 
-func NewService(repository Repository) *QuestionService {
-	return &QuestionService{repository}
+// NewService returns the question service, without revisions no history is kept and without a
+// judge submissions are refused.
+func NewService(repository Repository, judge Judge, revisions RevisionRepository) *QuestionService {
+	return &QuestionService{repository, judge, revisions}
 }
 
+// Create saves a new question as a draft, editors only.
 func (s *QuestionService) Create(ctx context.Context, q *Algorithm) (*Algorithm, error) {
-	id, err := s.repository.Save(ctx, q)
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+
+	q.Normalize()
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	AssignTestCaseIDs(q.TestCases)
+	q.Status = StatusDraft
+
+	id, err := s.save(ctx, q)
 	q.ID = id
 	return q, err
 }
 
-func (s *QuestionService) Filter(ctx context.Context, f Filter) ([]Algorithm, error) {
-	return s.repository.Find(ctx, f.Tags, f.Difficulty)
+// Filter lists published questions unless the filter asks for other statuses, which only
+// editors may.
+func (s *QuestionService) Filter(ctx context.Context, f Filter) (*Page, error) {
+	f.Deleted = false
+	if len(f.Statuses) == 0 {
+		f.Statuses = []Status{StatusPublished}
+	}
+	f.Normalize()
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	if !onlyPublished(f.Statuses) {
+		if err := RequireRole(ctx, RoleEditor); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repository.Find(ctx, f)
 }
 
+// Get returns questions that are not published to editors only, to anyone else they do not exist.
 func (s *QuestionService) Get(ctx context.Context, id string) (*Algorithm, error) {
+	return s.published(ctx, id)
+}
+
+// SetStatus moves the question through the review workflow, see TransitionRole for who may
+// do what. Setting the status the question already has changes nothing. Unless it is
+// AnyVersion the version must match the current one like for Update.
+func (s *QuestionService) SetStatus(ctx context.Context, id string, status Status, version int) (*Algorithm, error) {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+	if !status.IsValid() {
+		var verr ValidationError
+		verr.Add("status", "must be one of %s, %s, %s, %s; got %q", StatusDraft, StatusInReview, StatusPublished, StatusArchived, status)
+		return nil, verr.Err()
+	}
+
+	q, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version != AnyVersion && version != q.Version {
+		return nil, ErrPreconditionFailed
+	}
+	if q.Status == status {
+		return q, nil
+	}
+
+	role, err := TransitionRole(q.Status, status)
+	if err != nil {
+		return nil, err
+	}
+	if err := RequireRole(ctx, role); err != nil {
+		return nil, err
+	}
+
+	// the write is conditional on the version read, so a racing change fails instead of being skipped
+	if err := s.repository.SetStatus(ctx, id, status, q.Version); err != nil {
+		return nil, err
+	}
+	q.Status, q.Version = status, q.Version+1
+	s.record(ctx, id)
+	return q, nil
+}
+
+// Delete moves the question to the trash, published questions only by reviewers like for Update.
+// Unless it is AnyVersion the version must match the current one.
+func (s *QuestionService) Delete(ctx context.Context, id string, version int) error {
+	if _, err := s.editable(ctx, id); err != nil {
+		return err
+	}
+	return s.repository.Delete(ctx, id, version)
+}
+
+// Trash lists the deleted questions that are not purged yet, editors only.
+func (s *QuestionService) Trash(ctx context.Context, f Filter) (*Page, error) {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+
+	f.Deleted = true
+	f.Normalize()
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.repository.Find(ctx, f)
+}
+
+// Undelete takes the question out of the trash as it was deleted, published questions only by
+// reviewers like for Delete.
+func (s *QuestionService) Undelete(ctx context.Context, id string) (*Algorithm, error) {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+	// the status can't change in the trash, so it is the one the question comes back with
+	q, err := s.repository.Trashed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := RequireRole(ctx, EditRole(q.Status)); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Undelete(ctx, id); err != nil {
+		return nil, err
+	}
 	return s.repository.Get(ctx, id)
 }
 
-func (s *QuestionService) Delete(ctx context.Context, id string) error {
-	return s.repository.Delete(ctx, id)
+// Purge removes the questions deleted before the given time for good, their revisions included,
+// and returns how many there were.
+func (s *QuestionService) Purge(ctx context.Context, before time.Time) (int, error) {
+	// questions purged before an error still lose their revisions
+	ids, err := s.repository.Purge(ctx, before)
+	if s.revisions != nil {
+		for _, id := range ids {
+			if rerr := s.revisions.DeleteRevisions(ctx, id); rerr != nil && err == nil {
+				err = fmt.Errorf("delete revisions of %s: %w", id, rerr)
+			}
+		}
+	}
+	return len(ids), err
 }
 
+// Update replaces the question but its test cases, editors only. Candidates never see the hidden
+// cases, so the test cases of q are ignored and left as they are stored. Unless it is
+// AnyVersion q.Version must match the stored one.
 func (s *QuestionService) Update(ctx context.Context, id string, q *Algorithm) error {
-	return s.repository.Update(ctx, id, q)
+	stored, err := s.editable(ctx, id)
+	if err != nil {
+		return err
+	}
+	q.TestCases = stored.TestCases
+
+	return s.replace(ctx, id, q, _updateFields...)
+}
+
+// Patch applies a patch to the stored question and writes the fields that changed, the patched
+// question is validated like a full update. Without a version the patch is applied again when
+// the question changes while it is being patched. Editors only, the patch document holds the
+// hidden test cases, which copy and test operations would otherwise reveal.
+func (s *QuestionService) Patch(ctx context.Context, id string, p Patch) (*Algorithm, error) {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		q, err := s.patch(ctx, id, p)
+		if p.Version == AnyVersion && errors.Is(err, ErrPreconditionFailed) && attempt < _patchAttempts {
+			continue
+		}
+		return q, err
+	}
+}
+
+func (s *QuestionService) patch(ctx context.Context, id string, p Patch) (*Algorithm, error) {
+	q, err := s.editable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Version != AnyVersion && p.Version != q.Version {
+		return nil, ErrPreconditionFailed
+	}
+
+	patched, err := p.Apply(q)
+	if err != nil {
+		return nil, err
+	}
+	patched.Normalize()
+	if err := patched.Validate(); err != nil {
+		return nil, err
+	}
+	AssignTestCaseIDs(patched.TestCases)
+
+	fields := ChangedFields(q, patched)
+	if len(fields) == 0 {
+		return q, nil
+	}
+	if err := s.update(ctx, id, patched, fields...); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// TestCases returns the full test case set including the hidden cases, editors only.
+func (s *QuestionService) TestCases(ctx context.Context, id string) ([]TestCase, error) {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+
+	q, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return q.TestCases, nil
+}
+
+func (s *QuestionService) TestCase(ctx context.Context, id, testCaseID string) (*TestCase, error) {
+	testCases, err := s.TestCases(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tc := range testCases {
+		if tc.ID == testCaseID {
+			return &tc, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTestCaseNotFound, testCaseID)
+}
+
+func (s *QuestionService) AddTestCase(ctx context.Context, id string, tc TestCase) (*TestCase, error) {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+	if err := tc.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.editable(ctx, id); err != nil {
+		return nil, err
+	}
+
+	tc.ID = ""
+	testCases := []TestCase{tc}
+	AssignTestCaseIDs(testCases)
+	if err := s.repository.AddTestCase(ctx, id, testCases[0]); err != nil {
+		return nil, err
+	}
+	s.record(ctx, id)
+	return &testCases[0], nil
+}
+
+func (s *QuestionService) UpdateTestCase(ctx context.Context, id string, tc TestCase) error {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return err
+	}
+	if err := tc.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.editable(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repository.UpdateTestCase(ctx, id, tc); err != nil {
+		return err
+	}
+	s.record(ctx, id)
+	return nil
+}
+
+// DeleteTestCase removes a single test case, the last one of a question cannot be removed.
+func (s *QuestionService) DeleteTestCase(ctx context.Context, id, testCaseID string) error {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return err
+	}
+
+	if _, err := s.editable(ctx, id); err != nil {
+		return err
+	}
+
+	err := s.repository.DeleteTestCase(ctx, id, testCaseID)
+	if errors.Is(err, ErrLastTestCase) {
+		var verr ValidationError
+		verr.Add("testCases", "at least one test case is required")
+		return verr.Err()
+	}
+	if err != nil {
+		return err
+	}
+	s.record(ctx, id)
+	return nil
+}
+
+// Import creates the questions of a bundle one by one and reports the outcome of each, a failing
+// question does not stop the others. Questions are matched to existing ones by slug.
+func (s *QuestionService) Import(ctx context.Context, items []ImportItem, opts ImportOptions) ([]ImportResult, error) {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+
+	results := make([]ImportResult, 0, len(items))
+	slugs := make(map[string]int, len(items))
+	for idx, item := range items {
+		if item.Err != nil {
+			results = append(results, ImportResult{Index: idx, Action: ImportFailed, Err: item.Err})
+			continue
+		}
+
+		q := item.Question
+		res := s.importOne(ctx, q, opts, slugs)
+		res.Index, res.Slug = idx, q.Slug
+		if res.Err == nil {
+			slugs[q.Slug] = idx
+		} else {
+			res.Action = ImportFailed
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (s *QuestionService) importOne(ctx context.Context, q *Algorithm, opts ImportOptions, slugs map[string]int) ImportResult {
+	q.Normalize()
+	if err := q.Validate(); err != nil {
+		return ImportResult{Err: err}
+	}
+	if idx, ok := slugs[q.Slug]; ok {
+		return ImportResult{Err: fmt.Errorf("%w: slug %q is already used by question %d of the bundle", ErrConflict, q.Slug, idx)}
+	}
+	AssignTestCaseIDs(q.TestCases)
+
+	existing, err := s.repository.FindBySlug(ctx, q.Slug)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		if opts.DryRun {
+			return ImportResult{Action: ImportCreated}
+		}
+		q.Status = StatusDraft
+		id, err := s.save(ctx, q)
+		return ImportResult{ID: id, Action: ImportCreated, Err: err}
+	case err != nil:
+		return ImportResult{Err: err}
+	case !opts.Upsert:
+		return ImportResult{ID: existing.ID, Err: fmt.Errorf("%w: slug %q already exists", ErrConflict, q.Slug)}
+	}
+
+	if err := RequireRole(ctx, EditRole(existing.Status)); err != nil {
+		return ImportResult{ID: existing.ID, Err: err}
+	}
+	if opts.DryRun {
+		return ImportResult{ID: existing.ID, Action: ImportUpdated}
+	}
+	// a question changed between the lookup and the update fails instead of being overwritten
+	q.Version = existing.Version
+	return ImportResult{ID: existing.ID, Action: ImportUpdated, Err: s.update(ctx, existing.ID, q)}
+}
+
+// Export passes every question matching the filter to fn page by page, hidden test cases included,
+// so it is for editors only. Questions of every status are exported unless the filter names some.
+// The limit, cursor and sort of the filter are ignored.
+func (s *QuestionService) Export(ctx context.Context, f Filter, fn func(q *Algorithm) error) error {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return err
+	}
+
+	f.Limit, f.Cursor, f.Sort = MaxLimit, "", SortCreated
+	f.Normalize()
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	for {
+		page, err := s.repository.Find(ctx, f)
+		if err != nil {
+			return err
+		}
+		for idx := range page.Items {
+			if err := fn(&page.Items[idx]); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		f.Cursor = page.NextCursor
+	}
+}
+
+// ReplaceTestCases swaps the full test case set of a question, editors only.
+func (s *QuestionService) ReplaceTestCases(ctx context.Context, id string, testCases []TestCase) error {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return err
+	}
+	if err := ValidateTestCases(testCases); err != nil {
+		return err
+	}
+	AssignTestCaseIDs(testCases)
+
+	q, err := s.editable(ctx, id)
+	if err != nil {
+		return err
+	}
+	q.TestCases = testCases
+	return s.update(ctx, id, q)
+}
+
+// Submit judges a solution, anonymous callers may not as it runs their code.
+func (s *QuestionService) Submit(ctx context.Context, id string, sub Submission) (*SubmissionResult, error) {
+	if err := RequireRole(ctx, RoleUser); err != nil {
+		return nil, err
+	}
+	if s.judge == nil {
+		return nil, ErrJudgeDisabled
+	}
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+
+	q, err := s.published(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.judge.Judge(ctx, sub, q.TestCases)
+	if err != nil {
+		return nil, err
+	}
+	return NewSubmissionResult(q.TestCases, results), nil
+}
+
+// Revisions lists the revisions of a question newest first, editors only.
+func (s *QuestionService) Revisions(ctx context.Context, id string) ([]Revision, error) {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+	if s.revisions == nil {
+		return nil, ErrRevisionsDisabled
+	}
+	if _, err := s.repository.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.revisions.FindRevisions(ctx, id)
+}
+
+func (s *QuestionService) Revision(ctx context.Context, id string, number int) (*Revision, error) {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+	if s.revisions == nil {
+		return nil, ErrRevisionsDisabled
+	}
+	// revisions of questions in the trash are left out like the questions themselves
+	if _, err := s.repository.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.revisions.GetRevision(ctx, id, number)
+}
+
+// Restore updates the question to how a revision recorded it, which is a new revision itself.
+// The status, slug and creation time stay as they are, statuses only change through SetStatus.
+// Unless it is AnyVersion the version must match the current one like for Update.
+func (s *QuestionService) Restore(ctx context.Context, id string, number, version int) (*Algorithm, error) {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+	if s.revisions == nil {
+		return nil, ErrRevisionsDisabled
+	}
+	stored, err := s.editable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.revisions.GetRevision(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+
+	q := rev.Question
+	q.ID, q.Slug, q.Status, q.CreatedAt = stored.ID, stored.Slug, stored.Status, stored.CreatedAt
+	q.Version = version
+	if err := s.replace(ctx, id, &q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// editable gets the question, unless the caller may not change it, see EditRole.
+func (s *QuestionService) editable(ctx context.Context, id string) (*Algorithm, error) {
+	if err := RequireRole(ctx, RoleEditor); err != nil {
+		return nil, err
+	}
+
+	q, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := RequireRole(ctx, EditRole(q.Status)); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// published gets the question, unless it is not published and the caller is no editor.
+func (s *QuestionService) published(ctx context.Context, id string) (*Algorithm, error) {
+	q, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if q.Status != StatusPublished && !UserFrom(ctx).Has(RoleEditor) {
+		return nil, ErrNotFound
+	}
+	return q, nil
+}
+
+// replace validates q as a whole and writes the given fields of it, every field when none are given.
+func (s *QuestionService) replace(ctx context.Context, id string, q *Algorithm, fields ...Field) error {
+	q.Normalize()
+	if err := q.Validate(); err != nil {
+		return err
+	}
+	AssignTestCaseIDs(q.TestCases)
+
+	return s.update(ctx, id, q, fields...)
+}
+
+// save and update write through the repository and record the result as a revision.
+func (s *QuestionService) save(ctx context.Context, q *Algorithm) (string, error) {
+	id, err := s.repository.Save(ctx, q)
+	if err != nil {
+		return "", err
+	}
+	s.record(ctx, id)
+	return id, nil
+}
+
+func (s *QuestionService) update(ctx context.Context, id string, q *Algorithm, fields ...Field) error {
+	if err := s.repository.Update(ctx, id, q, fields...); err != nil {
+		return err
+	}
+	s.record(ctx, id)
+	return nil
+}
+
+// record saves a revision of the question as it is stored now. Writes racing each other may
+// leave a single revision for both, the later one then records the changes of both. The write
+// already happened when it fails, so the failure is only logged and the next write records both.
+func (s *QuestionService) record(ctx context.Context, id string) {
+	if s.revisions == nil {
+		return
+	}
+	if err := s.recordRevision(ctx, id); err != nil {
+		log.Printf("question %s: %v\n", id, err)
+	}
+}
+
+func (s *QuestionService) recordRevision(ctx context.Context, id string) error {
+	q, err := s.repository.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("record revision: %w", err)
+	}
+	prev, err := s.revisions.LatestRevision(ctx, id)
+	if errors.Is(err, ErrRevisionNotFound) {
+		prev, err = nil, nil
+	}
+	if err != nil {
+		return fmt.Errorf("record revision: %w", err)
+	}
+	if prev != nil && prev.Number >= q.Version {
+		return nil
+	}
+
+	var author string
+	if user := UserFrom(ctx); user != nil {
+		author = user.Name
+	}
+	rev, err := NewRevision(prev, q, author)
+	if err != nil {
+		return fmt.Errorf("record revision: %w", err)
+	}
+	if err := s.revisions.SaveRevision(ctx, rev); err != nil && !errors.Is(err, ErrConflict) {
+		return fmt.Errorf("record revision: %w", err)
+	}
+	return nil
 }
@@ -2,7 +2,9 @@ package question_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/codigician/question"
 	"github.com/codigician/question/mocks"
@@ -14,78 +16,1099 @@ func TestCreate_RepositoryReturnsID_GetSameQuestionID(t *testing.T) {
 	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
 	mockRepository.EXPECT().Save(gomock.Any(), gomock.Any()).Return("1", nil)
 
-	service := question.NewService(mockRepository)
+	service := question.NewService(mockRepository, nil, nil)
 
-	q, err := service.Create(context.Background(), &question.Algorithm{})
+	q, err := service.Create(editorContext(), newValidQuestion())
 
 	assert.Nil(t, err)
 	assert.Equal(t, "1", q.ID)
 }
 
+func TestCreate_Anonymous_ReturnUnauthorizedWithoutCallingRepository(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Save(gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.Create(context.Background(), newValidQuestion())
+
+	assert.ErrorIs(t, err, question.ErrUnauthorized)
+}
+
+func TestCreate_InvalidQuestion_ReturnValidationErrWithoutCallingRepository(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Save(gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.Create(editorContext(), &question.Algorithm{Difficulty: "banana"})
+
+	var verr *question.ValidationError
+	assert.ErrorAs(t, err, &verr)
+	assert.Len(t, verr.Fields, 3)
+}
+
+func TestCreate_GivenQuestion_SaveNormalizedQuestion(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	expected := newValidQuestion()
+	expected.Slug = "title"
+	expected.Tags = []string{"binary tree", "bfs"}
+	expected.TestCases[0].ID = "tc-1"
+	expected.Status = question.StatusDraft
+	mockRepository.EXPECT().Save(gomock.Any(), expected).Return("1", nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	given := newValidQuestion()
+	given.Title = "  Title "
+	given.Difficulty = "Hard"
+	given.Tags = []string{"Binary  Tree", "bfs", "BFS", " "}
+	given.TestCases[0].ID = "tc-1"
+	_, err := service.Create(editorContext(), given)
+
+	assert.Nil(t, err)
+}
+
+func TestCreate_TestCasesWithoutID_AssignIDs(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Save(gomock.Any(), gomock.Any()).Return("1", nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	given := newValidQuestion()
+	given.TestCases = append(given.TestCases, question.TestCase{ID: "kept", Input: "2 2", Output: "4"})
+	q, err := service.Create(editorContext(), given)
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, q.TestCases[0].ID)
+	assert.Equal(t, "kept", q.TestCases[1].ID)
+}
+
 func TestCreate_RepositoryReturnsErr_ReturnErr(t *testing.T) {
 	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
 	mockRepository.EXPECT().Save(gomock.Any(), gomock.Any()).Return("", assert.AnError)
 
-	service := question.NewService(mockRepository)
+	service := question.NewService(mockRepository, nil, nil)
 
-	_, err := service.Create(context.Background(), &question.Algorithm{})
+	_, err := service.Create(editorContext(), newValidQuestion())
 
 	assert.NotNil(t, err)
 }
 
 func TestFilter_GivenFilter_ExpectRepositoryCallWithFilters(t *testing.T) {
 	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
-	mockRepository.EXPECT().Find(gomock.Any(), []string{"tree"}, question.Difficulty("hard")).
-		Return([]question.Algorithm{}, nil)
+	mockRepository.EXPECT().Find(gomock.Any(), question.Filter{
+		Tags:         []string{"tree"},
+		TagMatch:     question.TagMatchAny,
+		Difficulties: []question.Difficulty{"hard"},
+		Statuses:     []question.Status{question.StatusPublished},
+		Limit:        question.DefaultLimit,
+		Sort:         question.SortCreated,
+	}).Return(&question.Page{}, nil)
 
-	service := question.NewService(mockRepository)
+	service := question.NewService(mockRepository, nil, nil)
 
 	res, err := service.Filter(context.Background(), question.Filter{
-		Tags:       []string{"tree"},
-		Difficulty: "hard",
+		Tags:         []string{"Tree"},
+		Difficulties: []question.Difficulty{"hard"},
 	})
 
 	assert.Nil(t, err)
-	assert.Equal(t, 0, len(res))
+	assert.Equal(t, 0, len(res.Items))
+}
+
+func TestFilter_GivenQuery_SortByRelevance(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Find(gomock.Any(), question.Filter{
+		Query:    "binary search",
+		TagMatch: question.TagMatchAny,
+		Statuses: []question.Status{question.StatusPublished},
+		Limit:    question.DefaultLimit,
+		Sort:     question.SortRelevance,
+	}).Return(&question.Page{}, nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.Filter(context.Background(), question.Filter{Query: " binary search "})
+
+	assert.Nil(t, err)
+}
+
+func TestFilter_LimitAboveMax_CapLimit(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Find(gomock.Any(), question.Filter{
+		TagMatch: question.TagMatchAny,
+		Statuses: []question.Status{question.StatusPublished},
+		Limit:    question.MaxLimit,
+		Sort:     question.SortTitle,
+	}).Return(&question.Page{}, nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.Filter(context.Background(), question.Filter{Limit: 1000, Sort: question.SortTitle})
+
+	assert.Nil(t, err)
+}
+
+func TestFilter_InvalidFilter_ReturnErrWithoutCallingRepository(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Find(gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.Filter(context.Background(), question.Filter{
+		Sort:         "popularity",
+		TagMatch:     "some",
+		Difficulties: []question.Difficulty{"easy", "banana"},
+	})
+	var verr *question.ValidationError
+	assert.ErrorAs(t, err, &verr)
+	assert.Len(t, verr.Fields, 3)
+
+	_, err = service.Filter(context.Background(), question.Filter{Cursor: "not a cursor"})
+	assert.ErrorIs(t, err, question.ErrInvalidCursor)
 }
 
 func TestGet_GivenID_CallRepository(t *testing.T) {
 	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
 	mockRepository.EXPECT().Get(gomock.Any(), "1").
-		Return(&question.Algorithm{}, nil)
+		Return(&question.Algorithm{Status: question.StatusPublished}, nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.Get(context.Background(), "1")
+
+	assert.Nil(t, err)
+}
+
+func TestGet_Unpublished_HiddenFromNonEditors(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), "1").
+		Return(&question.Algorithm{Status: question.StatusDraft}, nil).Times(2)
 
-	service := question.NewService(mockRepository)
+	service := question.NewService(mockRepository, nil, nil)
 
 	_, err := service.Get(context.Background(), "1")
+	assert.ErrorIs(t, err, question.ErrNotFound)
 
+	_, err = service.Get(editorContext(), "1")
 	assert.Nil(t, err)
 }
 
-func TestDelete_GivenID_CallRepository(t *testing.T) {
+func TestFilter_UnpublishedStatuses_EditorsOnly(t *testing.T) {
 	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
-	mockRepository.EXPECT().Delete(gomock.Any(), "1").Return(nil)
+	mockRepository.EXPECT().Find(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, f question.Filter) (*question.Page, error) {
+			assert.Equal(t, []question.Status{question.StatusDraft, question.StatusInReview}, f.Statuses)
+			return &question.Page{}, nil
+		})
 
-	service := question.NewService(mockRepository)
+	service := question.NewService(mockRepository, nil, nil)
+	f := question.Filter{Statuses: []question.Status{question.StatusDraft, question.StatusInReview}}
 
-	err := service.Delete(context.Background(), "1")
+	_, err := service.Filter(context.Background(), f)
+	assert.ErrorIs(t, err, question.ErrUnauthorized)
 
+	_, err = service.Filter(editorContext(), f)
 	assert.Nil(t, err)
+
+	_, err = service.Filter(editorContext(), question.Filter{Statuses: []question.Status{"hidden"}})
+	var verr *question.ValidationError
+	assert.ErrorAs(t, err, &verr)
 }
 
-func TestUpdate_GivenIDAndQuestion_CallRepository(t *testing.T) {
+func TestSetStatus(t *testing.T) {
+	reviewer := question.WithUser(context.Background(), &question.User{Name: "grace", Role: question.RoleReviewer})
+	testCases := []struct {
+		scenario    string
+		ctx         context.Context
+		from, to    question.Status
+		version     int
+		written     bool
+		expectedErr error
+	}{
+		{scenario: "editor submits a draft for review", ctx: editorContext(), from: question.StatusDraft, to: question.StatusInReview, version: question.AnyVersion, written: true},
+		{scenario: "reviewer publishes a reviewed question", ctx: reviewer, from: question.StatusInReview, to: question.StatusPublished, version: 3, written: true},
+		{scenario: "editor cannot publish", ctx: editorContext(), from: question.StatusInReview, to: question.StatusPublished, version: question.AnyVersion, expectedErr: question.ErrForbidden},
+		{scenario: "reviewer cannot publish a draft", ctx: reviewer, from: question.StatusDraft, to: question.StatusPublished, version: question.AnyVersion, expectedErr: question.ErrForbidden},
+		{scenario: "archived questions are not reviewed", ctx: reviewer, from: question.StatusArchived, to: question.StatusInReview, version: question.AnyVersion, expectedErr: question.ErrInvalidTransition},
+		{scenario: "same status changes nothing", ctx: editorContext(), from: question.StatusDraft, version: question.AnyVersion, to: question.StatusDraft},
+		{scenario: "stale version", ctx: reviewer, from: question.StatusInReview, to: question.StatusPublished, version: 2, expectedErr: question.ErrPreconditionFailed},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+			mockRepository.EXPECT().Get(gomock.Any(), "1").Return(&question.Algorithm{ID: "1", Status: tC.from, Version: 3}, nil)
+			if tC.written {
+				mockRepository.EXPECT().SetStatus(gomock.Any(), "1", tC.to, 3).Return(nil)
+			}
+
+			service := question.NewService(mockRepository, nil, nil)
+
+			q, err := service.SetStatus(tC.ctx, "1", tC.to, tC.version)
+
+			if tC.expectedErr != nil {
+				assert.ErrorIs(t, err, tC.expectedErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tC.to, q.Status)
+			if tC.written {
+				assert.Equal(t, 4, q.Version)
+			}
+		})
+	}
+}
+
+func TestSetStatus_Anonymous_ReturnUnauthorizedWithoutCallingRepository(t *testing.T) {
 	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
-	mockQuestion := &question.Algorithm{
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.SetStatus(context.Background(), "1", question.StatusPublished, question.AnyVersion)
+
+	assert.ErrorIs(t, err, question.ErrUnauthorized)
+}
+
+func TestDelete_GivenIDAndVersion_CallRepository(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(&question.Algorithm{ID: "1", Status: question.StatusDraft}, nil)
+	mockRepository.EXPECT().Delete(gomock.Any(), "1", 3).Return(nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	err := service.Delete(editorContext(), "1", 3)
+
+	assert.Nil(t, err)
+}
+
+func TestDelete_Anonymous_ReturnUnauthorizedWithoutCallingRepository(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	err := service.Delete(context.Background(), "1", question.AnyVersion)
+
+	assert.ErrorIs(t, err, question.ErrUnauthorized)
+}
+
+func TestDelete_PublishedQuestion_RequireReviewer(t *testing.T) {
+	reviewer := question.WithUser(context.Background(), &question.User{Name: "grace", Role: question.RoleReviewer})
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(&question.Algorithm{ID: "1", Status: question.StatusPublished}, nil).Times(2)
+	mockRepository.EXPECT().Delete(gomock.Any(), "1", question.AnyVersion).Return(nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	assert.ErrorIs(t, service.Delete(editorContext(), "1", question.AnyVersion), question.ErrForbidden)
+	assert.Nil(t, service.Delete(reviewer, "1", question.AnyVersion))
+}
+
+func TestTrash_GivenEditor_FindDeletedQuestions(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Find(gomock.Any(), question.Filter{
+		TagMatch: question.TagMatchAny,
+		Deleted:  true,
+		Limit:    question.DefaultLimit,
+		Sort:     question.SortCreated,
+	}).Return(&question.Page{}, nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.Trash(editorContext(), question.Filter{})
+	assert.Nil(t, err)
+
+	_, err = service.Trash(context.Background(), question.Filter{})
+	assert.ErrorIs(t, err, question.ErrUnauthorized)
+}
+
+func TestFilter_DeletedFilter_FindQuestionsInUse(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Find(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, f question.Filter) (*question.Page, error) {
+			assert.False(t, f.Deleted)
+			return &question.Page{}, nil
+		})
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.Filter(context.Background(), question.Filter{Deleted: true})
+	assert.Nil(t, err)
+}
+
+func TestUndelete_GivenEditor_ReturnRestoredQuestion(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	gomock.InOrder(
+		mockRepository.EXPECT().Trashed(gomock.Any(), "1").Return(&question.Algorithm{ID: "1", Status: question.StatusDraft}, nil),
+		mockRepository.EXPECT().Undelete(gomock.Any(), "1").Return(nil),
+		mockRepository.EXPECT().Get(gomock.Any(), "1").Return(&question.Algorithm{ID: "1", Version: 2}, nil),
+	)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	q, err := service.Undelete(editorContext(), "1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "1", q.ID)
+}
+
+func TestUndelete_PublishedQuestion_RequireReviewer(t *testing.T) {
+	reviewer := question.WithUser(context.Background(), &question.User{Name: "grace", Role: question.RoleReviewer})
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Trashed(gomock.Any(), "1").Return(&question.Algorithm{ID: "1", Status: question.StatusPublished}, nil).Times(2)
+	mockRepository.EXPECT().Undelete(gomock.Any(), "1").Return(nil)
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(&question.Algorithm{ID: "1", Status: question.StatusPublished}, nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.Undelete(editorContext(), "1")
+	assert.ErrorIs(t, err, question.ErrForbidden)
+
+	q, err := service.Undelete(reviewer, "1")
+	assert.Nil(t, err)
+	assert.Equal(t, question.StatusPublished, q.Status)
+}
+
+func TestPurge_DeleteRevisionsOfPurgedQuestions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepository := mocks.NewMockRepository(ctrl)
+	mockRevisions := mocks.NewMockRevisionRepository(ctrl)
+	before := time.Date(2021, 11, 2, 0, 0, 0, 0, time.UTC)
+	purgeErr := errors.New("connection reset")
+	mockRepository.EXPECT().Purge(gomock.Any(), before).Return([]string{"1", "2"}, purgeErr)
+	mockRevisions.EXPECT().DeleteRevisions(gomock.Any(), "1").Return(nil)
+	mockRevisions.EXPECT().DeleteRevisions(gomock.Any(), "2").Return(nil)
+
+	service := question.NewService(mockRepository, nil, mockRevisions)
+
+	n, err := service.Purge(context.Background(), before)
+
+	assert.ErrorIs(t, err, purgeErr)
+	assert.Equal(t, 2, n)
+}
+
+func TestUpdate_GivenIDAndQuestion_KeepStoredTestCases(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	stored := newValidQuestion()
+	stored.TestCases = []question.TestCase{
+		{ID: "tc-1", Input: "1 2", Output: "3"},
+		{ID: "tc-2", Input: "2 2", Output: "4", Hidden: true},
+	}
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(stored, nil)
+	mockRepository.EXPECT().
+		Update(gomock.Any(), "1", gomock.Any(),
+			question.FieldSlug, question.FieldTitle, question.FieldContent, question.FieldTemplates,
+			question.FieldDifficulty, question.FieldTags, question.FieldEditorial).
+		DoAndReturn(func(_ context.Context, _ string, q *question.Algorithm, _ ...question.Field) error {
+			assert.Equal(t, "New Title", q.Title)
+			assert.Equal(t, stored.TestCases, q.TestCases)
+			return nil
+		})
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	// a question as candidates get it, without hidden cases and ids
+	updated := newValidQuestion()
+	updated.Title = "New Title"
+	err := service.Update(editorContext(), "1", updated)
+
+	assert.Nil(t, err)
+}
+
+func TestEdit_PublishedQuestion_RequireReviewer(t *testing.T) {
+	reviewer := question.WithUser(context.Background(), &question.User{Name: "grace", Role: question.RoleReviewer})
+	edits := map[string]func(s *question.QuestionService, ctx context.Context) error{
+		"update": func(s *question.QuestionService, ctx context.Context) error {
+			return s.Update(ctx, "1", newValidQuestion())
+		},
+		"patch": func(s *question.QuestionService, ctx context.Context) error {
+			_, err := s.Patch(ctx, "1", question.Patch{Type: question.PatchMerge, Body: []byte(`{"title":"New Title"}`), Version: question.AnyVersion})
+			return err
+		},
+		"replace test cases": func(s *question.QuestionService, ctx context.Context) error {
+			return s.ReplaceTestCases(ctx, "1", []question.TestCase{{Input: "1", Output: "1"}})
+		},
+		"add test case": func(s *question.QuestionService, ctx context.Context) error {
+			_, err := s.AddTestCase(ctx, "1", question.TestCase{Input: "1", Output: "1"})
+			return err
+		},
+		"delete test case": func(s *question.QuestionService, ctx context.Context) error {
+			return s.DeleteTestCase(ctx, "1", "tc-1")
+		},
+	}
+	for name, edit := range edits {
+		t.Run(name, func(t *testing.T) {
+			mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+			published := newValidQuestion()
+			published.Status = question.StatusPublished
+			mockRepository.EXPECT().Get(gomock.Any(), "1").Return(published, nil).AnyTimes()
+			mockRepository.EXPECT().Update(gomock.Any(), "1", gomock.Any(), gomock.Any()).Return(nil).MaxTimes(1)
+			mockRepository.EXPECT().AddTestCase(gomock.Any(), "1", gomock.Any()).Return(nil).MaxTimes(1)
+			mockRepository.EXPECT().DeleteTestCase(gomock.Any(), "1", "tc-1").Return(nil).MaxTimes(1)
+
+			service := question.NewService(mockRepository, nil, nil)
+
+			assert.ErrorIs(t, edit(service, editorContext()), question.ErrForbidden)
+			assert.Nil(t, edit(service, reviewer))
+		})
+	}
+}
+
+func TestUpdate_InvalidQuestion_ReturnValidationErr(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(newValidQuestion(), nil)
+	mockRepository.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	invalid := newValidQuestion()
+	invalid.Title = ""
+	err := service.Update(editorContext(), "1", invalid)
+
+	var verr *question.ValidationError
+	assert.ErrorAs(t, err, &verr)
+}
+
+func TestUpdate_Anonymous_ReturnUnauthorizedWithoutCallingRepository(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), gomock.Any()).Times(0)
+	mockRepository.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	err := service.Update(context.Background(), "1", newValidQuestion())
+
+	assert.ErrorIs(t, err, question.ErrUnauthorized)
+}
+
+func newValidQuestion() *question.Algorithm {
+	return &question.Algorithm{
 		Title:      "Title",
 		Content:    "Content",
-		Template:   "Templaet",
-		Difficulty: "hard",
+		Templates:  map[question.Language]string{question.Go: "package main"},
+		Difficulty: question.Hard,
+		TestCases:  []question.TestCase{{Input: "1 2", Output: "3"}},
+	}
+}
+
+func TestSubmit_GivenSubmission_JudgeAgainstQuestionTestCases(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepository := mocks.NewMockRepository(ctrl)
+	mockJudge := mocks.NewMockJudge(ctrl)
+
+	q := newValidQuestion()
+	q.TestCases = append(q.TestCases, question.TestCase{Input: "2 2", Output: "4", Hidden: true})
+	q.Status = question.StatusPublished
+	sub := question.Submission{Language: question.Python, Code: "print(3)"}
+
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(q, nil)
+	mockJudge.EXPECT().Judge(gomock.Any(), sub, q.TestCases).Return([]question.CaseResult{
+		{Verdict: question.Accepted},
+		{Verdict: question.WrongAnswer},
+	}, nil)
+
+	service := question.NewService(mockRepository, mockJudge, nil)
+
+	result, err := service.Submit(userContext(), "1", sub)
+
+	assert.Nil(t, err)
+	assert.Equal(t, question.WrongAnswer, result.Verdict)
+	assert.Len(t, result.Cases, 2)
+	assert.Equal(t, 1, result.Score)
+	assert.Equal(t, 2, result.MaxScore)
+}
+
+func TestTestCases_Anonymous_ReturnUnauthorizedWithoutCallingRepository(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.TestCases(context.Background(), "1")
+
+	assert.ErrorIs(t, err, question.ErrUnauthorized)
+}
+
+func TestReplaceTestCases_GivenEditor_UpdateQuestionWithNewSet(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	testCases := []question.TestCase{{Input: "5 5", Output: "10", Hidden: true, Weight: 4}}
+	expected := newValidQuestion()
+	expected.TestCases = testCases
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(newValidQuestion(), nil)
+	mockRepository.EXPECT().Update(gomock.Any(), "1", expected).Return(nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+	ctx := question.WithUser(context.Background(), &question.User{Name: "ada", Role: question.RoleEditor})
+
+	err := service.ReplaceTestCases(ctx, "1", testCases)
+
+	assert.Nil(t, err)
+}
+
+func TestReplaceTestCases_InvalidSet_ReturnValidationErr(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+	ctx := question.WithUser(context.Background(), &question.User{Name: "ada", Role: question.RoleAdmin})
+
+	err := service.ReplaceTestCases(ctx, "1", []question.TestCase{{Input: "1", Weight: -1}})
+
+	var verr *question.ValidationError
+	assert.ErrorAs(t, err, &verr)
+	assert.Len(t, verr.Fields, 2)
+}
+
+func TestTestCases_LegacyCasesWithoutID_WriteNothing(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(newValidQuestion(), nil)
+	mockRepository.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	testCases, err := service.TestCases(editorContext(), "1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, newValidQuestion().TestCases, testCases)
+}
+
+func TestTestCase_UnknownID_ReturnTestCaseNotFound(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	q := newValidQuestion()
+	q.TestCases[0].ID = "tc-1"
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(q, nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.TestCase(editorContext(), "1", "tc-2")
+
+	assert.ErrorIs(t, err, question.ErrTestCaseNotFound)
+}
+
+func TestAddTestCase_GivenTestCase_PushWithNewID(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(newValidQuestion(), nil)
+	mockRepository.EXPECT().AddTestCase(gomock.Any(), "1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, tc question.TestCase) error {
+			assert.NotEmpty(t, tc.ID)
+			assert.NotEqual(t, "client-id", tc.ID)
+			return nil
+		})
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	tc, err := service.AddTestCase(editorContext(), "1", question.TestCase{ID: "client-id", Input: "1", Output: "1"})
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, tc.ID)
+}
+
+func TestUpdateTestCase_InvalidTestCase_ReturnValidationErr(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().UpdateTestCase(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	err := service.UpdateTestCase(editorContext(), "1", question.TestCase{ID: "tc-1"})
+
+	var verr *question.ValidationError
+	assert.ErrorAs(t, err, &verr)
+}
+
+func TestDeleteTestCase(t *testing.T) {
+	testCases := []struct {
+		scenario        string
+		repositoryErr   error
+		expectedErr     error
+		validationError bool
+	}{
+		{scenario: "one of several cases"},
+		{scenario: "last remaining case", repositoryErr: question.ErrLastTestCase, validationError: true},
+		{scenario: "unknown case", repositoryErr: question.ErrTestCaseNotFound, expectedErr: question.ErrTestCaseNotFound},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+			mockRepository.EXPECT().Get(gomock.Any(), "1").Return(newValidQuestion(), nil)
+			mockRepository.EXPECT().DeleteTestCase(gomock.Any(), "1", "tc-1").Return(tC.repositoryErr)
+
+			service := question.NewService(mockRepository, nil, nil)
+
+			err := service.DeleteTestCase(editorContext(), "1", "tc-1")
+
+			var verr *question.ValidationError
+			assert.Equal(t, tC.validationError, errors.As(err, &verr))
+			if !tC.validationError {
+				assert.ErrorIs(t, err, tC.expectedErr)
+			}
+		})
 	}
-	mockRepository.EXPECT().Update(gomock.Any(), "1", mockQuestion).
-		Return(nil)
+}
+
+func TestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepository := mocks.NewMockRepository(ctrl)
 
-	service := question.NewService(mockRepository)
+	existing := newValidQuestion()
+	existing.ID, existing.Slug, existing.Version = "1", "existing", 7
+	mockRepository.EXPECT().FindBySlug(gomock.Any(), "existing").Return(existing, nil).Times(2)
+	mockRepository.EXPECT().FindBySlug(gomock.Any(), "new").Return(nil, question.ErrNotFound)
+	mockRepository.EXPECT().Save(gomock.Any(), gomock.Any()).Return("2", nil)
+	mockRepository.EXPECT().Update(gomock.Any(), "1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, q *question.Algorithm) error {
+			assert.Equal(t, 7, q.Version)
+			return nil
+		})
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	withSlug := func(slug string) *question.Algorithm {
+		q := newValidQuestion()
+		q.Slug = slug
+		return q
+	}
+	invalid := withSlug("invalid")
+	invalid.TestCases = nil
 
-	err := service.Update(context.Background(), "1", mockQuestion)
+	unreadable := &question.DocumentError{Index: 4, Err: errors.New("unknown field")}
+	results, err := service.Import(editorContext(), []question.ImportItem{
+		{Question: withSlug("new")}, {Question: withSlug("existing")}, {Question: invalid}, {Question: withSlug("new")}, {Err: unreadable},
+	}, question.ImportOptions{Upsert: true})
 
 	assert.Nil(t, err)
+	assert.Len(t, results, 5)
+	assert.Equal(t, question.ImportResult{Index: 0, Slug: "new", ID: "2", Action: question.ImportCreated}, results[0])
+	assert.Equal(t, question.ImportResult{Index: 1, Slug: "existing", ID: "1", Action: question.ImportUpdated}, results[1])
+	assert.Equal(t, question.ImportFailed, results[2].Action)
+	var verr *question.ValidationError
+	assert.ErrorAs(t, results[2].Err, &verr)
+	assert.Equal(t, question.ImportFailed, results[3].Action)
+	assert.ErrorIs(t, results[3].Err, question.ErrConflict)
+	assert.Equal(t, question.ImportResult{Index: 4, Action: question.ImportFailed, Err: unreadable}, results[4])
+
+	results, err = service.Import(editorContext(), []question.ImportItem{{Question: withSlug("existing")}}, question.ImportOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, question.ImportFailed, results[0].Action)
+	assert.ErrorIs(t, results[0].Err, question.ErrConflict)
+}
+
+func TestImport_UpsertPublishedQuestionAsEditor_ReportForbidden(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	existing := newValidQuestion()
+	existing.ID, existing.Slug, existing.Status = "1", "title", question.StatusPublished
+	mockRepository.EXPECT().FindBySlug(gomock.Any(), "title").Return(existing, nil)
+	mockRepository.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	results, err := service.Import(editorContext(), []question.ImportItem{{Question: newValidQuestion()}}, question.ImportOptions{Upsert: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, question.ImportFailed, results[0].Action)
+	assert.ErrorIs(t, results[0].Err, question.ErrForbidden)
+}
+
+func TestImport_DryRun_WriteNothing(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().FindBySlug(gomock.Any(), "title").Return(nil, question.ErrNotFound)
+	mockRepository.EXPECT().Save(gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	results, err := service.Import(editorContext(), []question.ImportItem{{Question: newValidQuestion()}}, question.ImportOptions{DryRun: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []question.ImportResult{{Slug: "title", Action: question.ImportCreated}}, results)
+}
+
+func TestImport_Anonymous_ReturnUnauthorized(t *testing.T) {
+	service := question.NewService(mocks.NewMockRepository(gomock.NewController(t)), nil, nil)
+
+	_, err := service.Import(context.Background(), []question.ImportItem{{Question: newValidQuestion()}}, question.ImportOptions{})
+
+	assert.ErrorIs(t, err, question.ErrUnauthorized)
+}
+
+func TestExport_FollowCursorsUntilLastPage(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	gomock.InOrder(
+		mockRepository.EXPECT().Find(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, f question.Filter) (*question.Page, error) {
+				assert.Equal(t, question.MaxLimit, f.Limit)
+				assert.Equal(t, []string{"tree"}, f.Tags)
+				assert.Empty(t, f.Cursor)
+				return &question.Page{Items: []question.Algorithm{{ID: "1"}, {ID: "2"}}, NextCursor: "next"}, nil
+			}),
+		mockRepository.EXPECT().Find(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, f question.Filter) (*question.Page, error) {
+				assert.Equal(t, "next", f.Cursor)
+				return &question.Page{Items: []question.Algorithm{{ID: "3"}}}, nil
+			}),
+	)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	var ids []string
+	err := service.Export(editorContext(), question.Filter{Tags: []string{"tree"}, Limit: 5, Cursor: "ignored"}, func(q *question.Algorithm) error {
+		ids = append(ids, q.ID)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+}
+
+func editorContext() context.Context {
+	return question.WithUser(context.Background(), &question.User{Name: "ada", Role: question.RoleEditor})
+}
+
+func userContext() context.Context {
+	return question.WithUser(context.Background(), &question.User{Name: "alan", Role: question.RoleUser})
+}
+
+func TestSubmit_InvalidSubmission_ReturnValidationErr(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepository := mocks.NewMockRepository(ctrl)
+	mockRepository.EXPECT().Get(gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, mocks.NewMockJudge(ctrl), nil)
+
+	_, err := service.Submit(userContext(), "1", question.Submission{})
+
+	var verr *question.ValidationError
+	assert.ErrorAs(t, err, &verr)
+	assert.Len(t, verr.Fields, 2)
+}
+
+func TestSubmit_WithoutJudge_ReturnErrJudgeDisabled(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.Submit(userContext(), "1", question.Submission{Language: question.Python, Code: "print(3)"})
+
+	assert.ErrorIs(t, err, question.ErrJudgeDisabled)
+}
+
+func TestSubmit_Anonymous_ReturnUnauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service := question.NewService(mocks.NewMockRepository(ctrl), mocks.NewMockJudge(ctrl), nil)
+
+	_, err := service.Submit(context.Background(), "1", question.Submission{Language: question.Python, Code: "print(3)"})
+
+	assert.ErrorIs(t, err, question.ErrUnauthorized)
+}
+
+func TestPatch_WriteChangedFieldsOnly(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	stored := newValidQuestion()
+	stored.ID, stored.Slug, stored.Version = "1", "title", 3
+	stored.TestCases[0].ID = "tc-1"
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(stored, nil)
+	mockRepository.EXPECT().
+		Update(gomock.Any(), "1", gomock.Any(), question.FieldTitle, question.FieldTags).
+		DoAndReturn(func(_ context.Context, _ string, q *question.Algorithm, _ ...question.Field) error {
+			assert.Equal(t, 3, q.Version)
+			q.Version = 4
+			return nil
+		})
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	actual, err := service.Patch(editorContext(), "1", question.Patch{
+		Type:    question.PatchMerge,
+		Body:    []byte(`{"title":"  New Title ","tags":["Trees"]}`),
+		Version: question.AnyVersion,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "New Title", actual.Title)
+	assert.Equal(t, []string{"trees"}, actual.Tags)
+	assert.Equal(t, 4, actual.Version)
+}
+
+func TestPatch_NothingChanged_WriteNothing(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	stored := newValidQuestion()
+	stored.Slug = "title"
+	stored.TestCases[0].ID = "tc-1"
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(stored, nil)
+	mockRepository.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	actual, err := service.Patch(editorContext(), "1", question.Patch{Type: question.PatchMerge, Body: []byte(`{"title":"Title"}`), Version: question.AnyVersion})
+
+	assert.Nil(t, err)
+	assert.Equal(t, stored, actual)
+}
+
+func TestPatch_InvalidResult_ReturnValidationErr(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(newValidQuestion(), nil)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	_, err := service.Patch(editorContext(), "1", question.Patch{Type: question.PatchMerge, Body: []byte(`{"testCases":[]}`), Version: question.AnyVersion})
+
+	var verr *question.ValidationError
+	assert.ErrorAs(t, err, &verr)
+}
+
+func TestPatch_Anonymous_ReturnUnauthorizedWithoutCallingRepository(t *testing.T) {
+	mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+	mockRepository.EXPECT().Get(gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, nil)
+
+	// a test operation on a hidden case would tell its content apart from a guess
+	_, err := service.Patch(context.Background(), "1", question.Patch{
+		Type:    question.PatchJSON,
+		Body:    []byte(`[{"op":"test","path":"/testCases/1/output","value":"4"}]`),
+		Version: question.AnyVersion,
+	})
+
+	assert.ErrorIs(t, err, question.ErrUnauthorized)
+}
+
+func TestPatch_Version(t *testing.T) {
+	testCases := []struct {
+		scenario      string
+		givenVersion  int
+		expectedGets  int
+		expectedCalls int
+	}{
+		{
+			scenario:      "Given no version it should apply the patch again after losing a race",
+			givenVersion:  question.AnyVersion,
+			expectedGets:  3,
+			expectedCalls: 3,
+		},
+		{
+			scenario:      "Given the stored version it should fail after losing a race",
+			givenVersion:  3,
+			expectedGets:  1,
+			expectedCalls: 1,
+		},
+		{
+			scenario:     "Given a stale version it should fail without writing",
+			givenVersion: 2,
+			expectedGets: 1,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.scenario, func(t *testing.T) {
+			mockRepository := mocks.NewMockRepository(gomock.NewController(t))
+			mockRepository.EXPECT().Get(gomock.Any(), "1").
+				DoAndReturn(func(context.Context, string) (*question.Algorithm, error) {
+					q := newValidQuestion()
+					q.Slug, q.Version = "title", 3
+					q.TestCases[0].ID = "tc-1"
+					return q, nil
+				}).Times(tC.expectedGets)
+			mockRepository.EXPECT().Update(gomock.Any(), "1", gomock.Any(), question.FieldTitle).
+				Return(question.ErrPreconditionFailed).Times(tC.expectedCalls)
+
+			service := question.NewService(mockRepository, nil, nil)
+
+			_, err := service.Patch(editorContext(), "1", question.Patch{
+				Type:    question.PatchMerge,
+				Body:    []byte(`{"title":"New Title"}`),
+				Version: tC.givenVersion,
+			})
+
+			assert.ErrorIs(t, err, question.ErrPreconditionFailed)
+		})
+	}
+}
+
+func TestUpdate_RecordRevisionOfStoredQuestion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepository := mocks.NewMockRepository(ctrl)
+	mockRevisions := mocks.NewMockRevisionRepository(ctrl)
+
+	prev := newValidQuestion()
+	prev.ID, prev.Slug, prev.Version = "1", "title", 1
+	prev.TestCases[0].ID = "tc-1"
+	stored := newValidQuestion()
+	stored.ID, stored.Slug, stored.Title, stored.Version = "1", "title", "New Title", 2
+	stored.TestCases[0].ID = "tc-1"
+
+	gomock.InOrder(
+		mockRepository.EXPECT().Get(gomock.Any(), "1").Return(prev, nil),
+		mockRepository.EXPECT().Update(gomock.Any(), "1", gomock.Any(), gomock.Any()).Return(nil),
+		mockRepository.EXPECT().Get(gomock.Any(), "1").Return(stored, nil),
+	)
+	mockRevisions.EXPECT().LatestRevision(gomock.Any(), "1").Return(&question.Revision{QuestionID: "1", Number: 1, Question: *prev}, nil)
+	mockRevisions.EXPECT().SaveRevision(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, rev *question.Revision) error {
+			assert.Equal(t, 2, rev.Number)
+			assert.Equal(t, "ada", rev.Author)
+			assert.Equal(t, []question.Field{question.FieldTitle}, rev.Fields)
+			assert.JSONEq(t, `{"title":"New Title"}`, string(rev.Diff))
+			assert.Equal(t, *stored, rev.Question)
+			return nil
+		})
+
+	service := question.NewService(mockRepository, nil, mockRevisions)
+
+	updated := newValidQuestion()
+	updated.Title = "New Title"
+	err := service.Update(editorContext(), "1", updated)
+
+	assert.Nil(t, err)
+}
+
+func TestSetStatus_RecordRevisionOfStatusChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepository := mocks.NewMockRepository(ctrl)
+	mockRevisions := mocks.NewMockRevisionRepository(ctrl)
+
+	prev := newValidQuestion()
+	prev.ID, prev.Status, prev.Version = "1", question.StatusDraft, 1
+	stored := *prev
+	stored.Status, stored.Version = question.StatusInReview, 2
+
+	gomock.InOrder(
+		mockRepository.EXPECT().Get(gomock.Any(), "1").Return(prev, nil),
+		mockRepository.EXPECT().SetStatus(gomock.Any(), "1", question.StatusInReview, 1).Return(nil),
+		mockRepository.EXPECT().Get(gomock.Any(), "1").Return(&stored, nil),
+	)
+	mockRevisions.EXPECT().LatestRevision(gomock.Any(), "1").Return(&question.Revision{QuestionID: "1", Number: 1, Question: *prev}, nil)
+	mockRevisions.EXPECT().SaveRevision(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, rev *question.Revision) error {
+			assert.Equal(t, 2, rev.Number)
+			assert.Equal(t, []question.Field{question.FieldStatus}, rev.Fields)
+			assert.JSONEq(t, `{"status":"in_review"}`, string(rev.Diff))
+			return nil
+		})
+
+	service := question.NewService(mockRepository, nil, mockRevisions)
+
+	_, err := service.SetStatus(editorContext(), "1", question.StatusInReview, question.AnyVersion)
+
+	assert.Nil(t, err)
+}
+
+func TestCreate_RecordFirstRevision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepository := mocks.NewMockRepository(ctrl)
+	mockRevisions := mocks.NewMockRevisionRepository(ctrl)
+
+	stored := newValidQuestion()
+	stored.ID, stored.Version = "1", 1
+	mockRepository.EXPECT().Save(gomock.Any(), gomock.Any()).Return("1", nil)
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(stored, nil)
+	mockRevisions.EXPECT().LatestRevision(gomock.Any(), "1").Return(nil, question.ErrRevisionNotFound)
+	mockRevisions.EXPECT().SaveRevision(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, rev *question.Revision) error {
+			assert.Equal(t, 1, rev.Number)
+			assert.Equal(t, "ada", rev.Author)
+			assert.Equal(t, question.Fields, rev.Fields)
+			return nil
+		})
+
+	service := question.NewService(mockRepository, nil, mockRevisions)
+
+	_, err := service.Create(editorContext(), newValidQuestion())
+
+	assert.Nil(t, err)
+}
+
+func TestUpdate_RevisionAlreadyRecorded_SaveNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepository := mocks.NewMockRepository(ctrl)
+	mockRevisions := mocks.NewMockRevisionRepository(ctrl)
+
+	stored := newValidQuestion()
+	stored.Version = 3
+	mockRepository.EXPECT().Update(gomock.Any(), "1", gomock.Any(), gomock.Any()).Return(nil)
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(stored, nil).Times(2)
+	mockRevisions.EXPECT().LatestRevision(gomock.Any(), "1").Return(&question.Revision{Number: 3}, nil)
+	mockRevisions.EXPECT().SaveRevision(gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, mockRevisions)
+
+	assert.Nil(t, service.Update(editorContext(), "1", newValidQuestion()))
+}
+
+func TestUpdate_RecordingRevisionFails_KeepUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepository := mocks.NewMockRepository(ctrl)
+	mockRevisions := mocks.NewMockRevisionRepository(ctrl)
+
+	stored := newValidQuestion()
+	stored.Version = 3
+	mockRepository.EXPECT().Update(gomock.Any(), "1", gomock.Any(), gomock.Any()).Return(nil)
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(stored, nil).Times(2)
+	mockRevisions.EXPECT().LatestRevision(gomock.Any(), "1").Return(nil, errors.New("connection reset"))
+
+	service := question.NewService(mockRepository, nil, mockRevisions)
+
+	assert.Nil(t, service.Update(editorContext(), "1", newValidQuestion()))
+}
+
+func TestRevisions_WithoutRevisionRepository_ReturnErrRevisionsDisabled(t *testing.T) {
+	service := question.NewService(mocks.NewMockRepository(gomock.NewController(t)), nil, nil)
+
+	_, err := service.Revisions(editorContext(), "1")
+	assert.ErrorIs(t, err, question.ErrRevisionsDisabled)
+	_, err = service.Revision(editorContext(), "1", 1)
+	assert.ErrorIs(t, err, question.ErrRevisionsDisabled)
+	_, err = service.Restore(editorContext(), "1", 1, question.AnyVersion)
+	assert.ErrorIs(t, err, question.ErrRevisionsDisabled)
+}
+
+func TestRestore_UpdateToRevision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepository := mocks.NewMockRepository(ctrl)
+	mockRevisions := mocks.NewMockRevisionRepository(ctrl)
+
+	createdAt := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := newValidQuestion()
+	old.ID, old.Slug, old.Title, old.Status, old.Version = "1", "title", "Old Title", question.StatusDraft, 1
+	current := newValidQuestion()
+	current.ID, current.Slug, current.Status, current.CreatedAt, current.Version = "1", "new-title", question.StatusInReview, createdAt, 4
+	mockRevisions.EXPECT().GetRevision(gomock.Any(), "1", 1).Return(&question.Revision{QuestionID: "1", Number: 1, Question: *old}, nil)
+	mockRepository.EXPECT().Update(gomock.Any(), "1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, q *question.Algorithm, _ ...question.Field) error {
+			assert.Equal(t, "Old Title", q.Title)
+			assert.Equal(t, "new-title", q.Slug)
+			assert.Equal(t, 4, q.Version)
+			q.Version = 5
+			return nil
+		})
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(current, nil).Times(2)
+	mockRevisions.EXPECT().LatestRevision(gomock.Any(), "1").Return(&question.Revision{Number: 5}, nil)
+
+	service := question.NewService(mockRepository, nil, mockRevisions)
+
+	restored, err := service.Restore(editorContext(), "1", 1, 4)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Old Title", restored.Title)
+	assert.Equal(t, question.StatusInReview, restored.Status)
+	assert.Equal(t, createdAt, restored.CreatedAt)
+	assert.Equal(t, 5, restored.Version)
+
+	_, err = service.Restore(context.Background(), "1", 1, question.AnyVersion)
+	assert.ErrorIs(t, err, question.ErrUnauthorized)
+}
+
+func TestRevision_DeletedQuestion_ReturnNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepository := mocks.NewMockRepository(ctrl)
+	mockRevisions := mocks.NewMockRevisionRepository(ctrl)
+	mockRepository.EXPECT().Get(gomock.Any(), "1").Return(nil, question.ErrNotFound)
+	mockRevisions.EXPECT().GetRevision(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	service := question.NewService(mockRepository, nil, mockRevisions)
+
+	_, err := service.Revision(editorContext(), "1", 1)
+
+	assert.ErrorIs(t, err, question.ErrNotFound)
 }
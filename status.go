@@ -0,0 +1,67 @@
+package question
+
+import "fmt"
+
+// Status is where a question is in its review workflow, only published questions are public.
+type Status string
+
+const (
+	StatusDraft     Status = "draft"
+	StatusInReview  Status = "in_review"
+	StatusPublished Status = "published"
+	StatusArchived  Status = "archived"
+)
+
+// _transitions lists the role each status change requires, changes missing here are not allowed.
+// Editors write and submit questions, reviewers publish them.
+var _transitions = map[Status]map[Status]Role{
+	StatusDraft: {
+		StatusInReview:  RoleEditor,
+		StatusPublished: RoleAdmin,
+	},
+	StatusInReview: {
+		StatusDraft:     RoleEditor,
+		StatusPublished: RoleReviewer,
+	},
+	StatusPublished: {
+		StatusDraft:    RoleReviewer,
+		StatusArchived: RoleReviewer,
+	},
+	StatusArchived: {
+		StatusDraft:     RoleEditor,
+		StatusPublished: RoleReviewer,
+	},
+}
+
+func (s Status) IsValid() bool {
+	return s == StatusDraft || s == StatusInReview || s == StatusPublished || s == StatusArchived
+}
+
+// TransitionRole returns the role required to move a question from one status to another,
+// ErrInvalidTransition when the workflow does not allow it.
+func TransitionRole(from, to Status) (Role, error) {
+	role, ok := _transitions[from][to]
+	if !ok {
+		return "", fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+	return role, nil
+}
+
+// EditRole returns the role required to change a question in the given status. Published
+// questions are only changed by reviewers, so no edit goes live without a review.
+func EditRole(status Status) Role {
+	if status == StatusPublished {
+		return RoleReviewer
+	}
+	return RoleEditor
+}
+
+// onlyPublished tells whether a status filter shows nothing but published questions.
+func onlyPublished(statuses []Status) bool {
+	for _, s := range statuses {
+		if s != StatusPublished {
+			return false
+		}
+	}
+	return len(statuses) > 0
+}